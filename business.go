@@ -0,0 +1,113 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BusinessHours is a daily working window given as offsets from midnight, e.g.
+// {9 * Hour, 17 * Hour} for 09:00-17:00.
+type BusinessHours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// BusinessCalendar defines when business time elapses.
+type BusinessCalendar struct {
+	// Hours holds the working window for each weekday, indexed by time.Weekday. An
+	// empty window (Start == End) marks a day off.
+	Hours [7]BusinessHours
+
+	// Holidays lists days off regardless of weekday. Only the year, month, and day
+	// of each entry are used.
+	Holidays []time.Time
+}
+
+// isHoliday reports whether the given date is listed in c.Holidays.
+func (c *BusinessCalendar) isHoliday(year int, month time.Month, day int) bool {
+	for _, h := range c.Holidays {
+		if y, m, d := h.Date(); y == year && m == month && d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// AddBusinessTimespan parses a time span string (see ParseTimespan) and adds it to t,
+// counting only business time as defined by cal. Time outside the working windows and
+// on holidays is skipped, e.g. adding "2h" at 16:00 with 09:00-17:00 working hours
+// yields 10:00 on the next working day. Working windows are evaluated in the timezone
+// of t.
+func AddBusinessTimespan(t time.Time, s string, cal BusinessCalendar) (time.Time, error) {
+	d, err := ParseTimespan(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if d < 0 {
+		return time.Time{}, fmt.Errorf("expected non-negative time span, got %q", s)
+	}
+
+	working := false
+	for wd, h := range cal.Hours {
+		if h.Start < 0 || h.End > Day || h.Start > h.End {
+			return time.Time{}, fmt.Errorf("expected business hours within a day, got %s-%s on %s",
+				h.Start, h.End, time.Weekday(wd))
+		}
+		working = working || h.Start < h.End
+	}
+	if !working {
+		return time.Time{}, errors.New("expected business hours on at least one weekday")
+	}
+
+	if d == 0 {
+		return t, nil
+	}
+
+	// walk day by day, consuming the duration within each working window
+	loc := t.Location()
+	year, month, day := t.Date()
+	for {
+		date := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		year, month, day = date.Date() // normalize after day++
+		h := cal.Hours[date.Weekday()]
+		if h.Start < h.End && !cal.isHoliday(year, month, day) {
+			start := time.Date(year, month, day, 0, 0, 0, int(h.Start), loc)
+			end := time.Date(year, month, day, 0, 0, 0, int(h.End), loc)
+			if t.After(start) {
+				start = t
+			}
+			if start.Before(end) {
+				avail := end.Sub(start)
+				if d <= avail {
+					return start.Add(d), nil
+				}
+				d -= avail
+			}
+		}
+		day++
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime_test
+
+import (
+	"testing"
+	"time"
+
+	systemdtime "gitlab.com/allddd/go-systemd-time"
+)
+
+func TestAddBusinessTimespan(t *testing.T) {
+	office := systemdtime.BusinessHours{Start: 9 * systemdtime.Hour, End: 17 * systemdtime.Hour}
+	cal := systemdtime.BusinessCalendar{
+		Hours: [7]systemdtime.BusinessHours{
+			time.Monday:    office,
+			time.Tuesday:   office,
+			time.Wednesday: office,
+			time.Thursday:  office,
+			time.Friday:    office,
+		},
+		Holidays: []time.Time{time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC)},
+	}
+	cases := []struct {
+		start     time.Time
+		input     string
+		expect    time.Time
+		expectErr bool
+	}{
+		// within a day
+		{time.Date(2009, 11, 10, 10, 0, 0, 0, time.UTC), "2h", time.Date(2009, 11, 10, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2009, 11, 10, 15, 0, 0, 0, time.UTC), "2h", time.Date(2009, 11, 10, 17, 0, 0, 0, time.UTC), false},
+		{time.Date(2009, 11, 10, 7, 0, 0, 0, time.UTC), "1h", time.Date(2009, 11, 10, 10, 0, 0, 0, time.UTC), false},
+		// across end of business day (and holiday on Wednesday)
+		{time.Date(2009, 11, 10, 16, 0, 0, 0, time.UTC), "2h", time.Date(2009, 11, 12, 10, 0, 0, 0, time.UTC), false},
+		{time.Date(2009, 11, 9, 16, 0, 0, 0, time.UTC), "2h", time.Date(2009, 11, 10, 10, 0, 0, 0, time.UTC), false},
+		{time.Date(2009, 11, 10, 20, 0, 0, 0, time.UTC), "30min", time.Date(2009, 11, 12, 9, 30, 0, 0, time.UTC), false},
+		// across weekend
+		{time.Date(2009, 11, 13, 16, 0, 0, 0, time.UTC), "2h", time.Date(2009, 11, 16, 10, 0, 0, 0, time.UTC), false},
+		{time.Date(2009, 11, 14, 12, 0, 0, 0, time.UTC), "1h", time.Date(2009, 11, 16, 10, 0, 0, 0, time.UTC), false},
+		{time.Date(2009, 11, 12, 9, 0, 0, 0, time.UTC), "3d", time.Date(2009, 11, 24, 17, 0, 0, 0, time.UTC), false},
+		// across month
+		{time.Date(2009, 11, 30, 16, 0, 0, 0, time.UTC), "4h", time.Date(2009, 12, 1, 12, 0, 0, 0, time.UTC), false},
+		// zero
+		{time.Date(2009, 11, 14, 12, 0, 0, 0, time.UTC), "0", time.Date(2009, 11, 14, 12, 0, 0, 0, time.UTC), false},
+		// error
+		{time.Date(2009, 11, 10, 10, 0, 0, 0, time.UTC), "invalid", time.Time{}, true},
+		{time.Date(2009, 11, 10, 10, 0, 0, 0, time.UTC), "-1h", time.Time{}, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.AddBusinessTimespan(tc.start, tc.input, cal)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%v + %q: expected error, got nil", tc.start, tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v + %q: unexpected error: %v", tc.start, tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%v + %q: expected %v, got %v", tc.start, tc.input, tc.expect, got)
+		}
+	}
+
+	start := time.Date(2009, 11, 10, 10, 0, 0, 0, time.UTC)
+	if _, err := systemdtime.AddBusinessTimespan(start, "1h", systemdtime.BusinessCalendar{}); err == nil {
+		t.Errorf("empty calendar: expected error, got nil")
+	}
+	invalid := systemdtime.BusinessCalendar{}
+	invalid.Hours[time.Monday] = systemdtime.BusinessHours{Start: 17 * systemdtime.Hour, End: 9 * systemdtime.Hour}
+	if _, err := systemdtime.AddBusinessTimespan(start, "1h", invalid); err == nil {
+		t.Errorf("inverted hours: expected error, got nil")
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// calendarShorthands maps the shorthands accepted by ParseCalendar to their full form.
+var calendarShorthands = map[string]string{
+	"minutely":     "*-*-* *:*:00",
+	"hourly":       "*-*-* *:00:00",
+	"daily":        "*-*-* 00:00:00",
+	"weekly":       "Mon *-*-* 00:00:00",
+	"monthly":      "*-*-01 00:00:00",
+	"yearly":       "*-01-01 00:00:00",
+	"annually":     "*-01-01 00:00:00",
+	"quarterly":    "*-01,04,07,10-01 00:00:00",
+	"semiannually": "*-01,07-01 00:00:00",
+}
+
+// CalendarValue is a value or range of values of a calendar event component. Start
+// and End are inclusive and equal for a single value. If Step is positive, the value
+// repeats every Step from Start up to End, e.g. minutes "0/15" are 0, 15, 30, and 45.
+type CalendarValue struct {
+	Start int
+	End   int
+	Step  int
+}
+
+// matches reports whether the component value v is matched by cv.
+func (cv CalendarValue) matches(v int) bool {
+	if v < cv.Start || v > cv.End {
+		return false
+	}
+	return cv.Step <= 0 || (v-cv.Start)%cv.Step == 0
+}
+
+// CalendarSpec is a recurring calendar event as parsed by ParseCalendar. Each
+// component lists the values it matches; an empty component matches any value ("*").
+type CalendarSpec struct {
+	Weekdays []time.Weekday
+	Year     []CalendarValue
+	Month    []CalendarValue
+	Day      []CalendarValue
+	Hour     []CalendarValue
+	Minute   []CalendarValue
+	Second   []CalendarValue
+
+	// Location is the timezone the event is evaluated in. If nil, the location of the
+	// time passed to Next is used.
+	Location *time.Location
+}
+
+// matchCalendar reports whether v is matched by any of the values in c, or c is empty.
+func matchCalendar(c []CalendarValue, v int) bool {
+	if len(c) == 0 {
+		return true
+	}
+	for _, cv := range c {
+		if cv.matches(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// readCalendarNum reads a value of the calendar event component name from item and
+// returns the value and any error. The value must be in range [minValue, maxValue].
+func readCalendarNum(s, item, name string, minValue, maxValue int) (int, error) {
+	n, i, err := readNum(item, 0)
+	if err != nil || i != len(item) {
+		return 0, fmt.Errorf("expected %s, got %q in %q", name, item, s)
+	}
+	if n < minValue || n > maxValue {
+		return 0, fmt.Errorf("expected %s in range %d-%d, got %d in %q", name, minValue, maxValue, n, s)
+	}
+	return n, nil
+}
+
+// handleCalendarComponent parses a component of a calendar event from field and
+// returns the values, or nil for "*", and any error. Values are comma-separated, may
+// be ranges "start..end", may repeat with "start/step" or "start..end/step" ("/step"
+// and "*/step" start at minValue), and must be in range [minValue, maxValue].
+func handleCalendarComponent(s, field, name string, minValue, maxValue int) ([]CalendarValue, error) {
+	if field == "*" {
+		return nil, nil
+	}
+
+	var values []CalendarValue
+	for _, item := range strings.Split(field, ",") {
+		var cv CalendarValue
+		var err error
+		start, step, hasStep := item, "", false
+		if i := strings.IndexByte(item, '/'); i >= 0 {
+			start, step, hasStep = item[:i], item[i+1:], true
+		}
+
+		// parse start and (optional) end, defaulting to minValue if only a step is given
+		switch {
+		case hasStep && (start == "" || start == "*"):
+			cv.Start, cv.End = minValue, maxValue
+		case strings.Contains(start, ".."):
+			i := strings.Index(start, "..")
+			if cv.Start, err = readCalendarNum(s, start[:i], name, minValue, maxValue); err != nil {
+				return nil, err
+			}
+			if cv.End, err = readCalendarNum(s, start[i+2:], name, minValue, maxValue); err != nil {
+				return nil, err
+			}
+			if cv.End < cv.Start {
+				return nil, fmt.Errorf("expected %s range end not before start, got %q in %q", name, start, s)
+			}
+		default:
+			if cv.Start, err = readCalendarNum(s, start, name, minValue, maxValue); err != nil {
+				return nil, err
+			}
+			cv.End = cv.Start
+			if hasStep {
+				cv.End = maxValue
+			}
+		}
+
+		// parse (optional) step
+		if hasStep {
+			n, j, err := readNum(step, 0)
+			if err != nil || j != len(step) || n < 1 {
+				return nil, fmt.Errorf("expected positive %s step, got %q in %q", name, step, s)
+			}
+			cv.Step = n
+		}
+
+		values = append(values, cv)
+	}
+	return values, nil
+}
+
+// handleCalendarWeekdays parses a comma-separated list of weekdays or weekday ranges
+// ("Mon..Fri") from field and returns the weekdays and any error. Ranges wrap around
+// the end of the week, e.g. "Sat..Mon" is Saturday, Sunday, and Monday.
+func handleCalendarWeekdays(s, field string) ([]time.Weekday, error) {
+	var weekdays []time.Weekday
+	for _, item := range strings.Split(field, ",") {
+		first, last := item, item
+		if i := strings.Index(item, ".."); i >= 0 {
+			first, last = item[:i], item[i+2:]
+		}
+		start, i, found := handleWeekday(first, 0)
+		if !found || i != len(first) {
+			return nil, fmt.Errorf("expected weekday, got %q in %q", first, s)
+		}
+		end, i, found := handleWeekday(last, 0)
+		if !found || i != len(last) {
+			return nil, fmt.Errorf("expected weekday, got %q in %q", last, s)
+		}
+		for wd := start; ; wd = (wd + 1) % 7 {
+			weekdays = append(weekdays, wd)
+			if wd == end {
+				break
+			}
+		}
+	}
+	return weekdays, nil
+}
+
+// ParseCalendar parses a calendar event string and returns the recurring event.
+//
+// Calendar events consist of optional weekdays, date, time, and timezone, separated by
+// spaces. Weekdays are given as a comma-separated list of abbreviated ("Mon") or full
+// ("Monday") English names or ranges ("Mon..Fri", "Sat..Sun"); if omitted, any weekday
+// matches. The date is given as Y-M-D or M-D (the year defaults to "*") and defaults to
+// "*-*-*". The time is given as H:M:S or H:M (seconds default to 00) and defaults to
+// 00:00:00. Each date and time component is "*" (any value) or a comma-separated list
+// of values or ranges ("1..7"), each of which may repeat with "start/step" (e.g.
+// minutes "0/15" are 0, 15, 30, and 45; "/15" starts at the lowest value of the
+// component) or "start..end/step". The timezone is "UTC" or an IANA timezone database
+// entry (e.g. "Asia/Tokyo"); if omitted, the event is evaluated in the timezone of the
+// time passed to Next.
+//
+// The shorthands "minutely", "hourly", "daily", "weekly", "monthly", "yearly" (or
+// "annually"), "quarterly", and "semiannually" may be used instead of the weekday,
+// date, and time, optionally followed by a timezone (e.g. "daily UTC").
+//
+// Examples for valid calendar events:
+//
+//	daily
+//	*-*-* 02:00:00
+//	Mon,Fri *-*-* 00:00:00
+//	Mon..Fri *-*-* 22:00:00
+//	*-*-1..7 00:00:00
+//	*-*-01 12:00
+//	*:0/15
+//	*-*-1/2 00:00:00
+//	Sat 08:30 Europe/Berlin
+//	2009-11-10 18:15:22 UTC
+func ParseCalendar(s string) (*CalendarSpec, error) {
+	if isBlank(s) {
+		return nil, fmt.Errorf("expected calendar event, got %q: %w", s, ErrEmptyInput)
+	}
+
+	tokens := strings.Fields(s)
+	if len(tokens) == 0 { // only non-ASCII whitespace
+		return nil, fmt.Errorf("expected calendar event, got %q: %w", s, ErrEmptyInput)
+	}
+	if full, ok := calendarShorthands[tokens[0]]; ok {
+		tokens = append(strings.Fields(full), tokens[1:]...)
+	}
+
+	spec := &CalendarSpec{}
+	var err error
+	i := 0
+
+	// parse (optional) weekdays
+	if c := tokens[i][0]; (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+		spec.Weekdays, err = handleCalendarWeekdays(s, tokens[i])
+		if err != nil {
+			return nil, err
+		}
+		i++
+	}
+
+	// parse (optional) date
+	if i < len(tokens) && strings.IndexByte(tokens[i], '-') >= 0 {
+		parts := strings.Split(tokens[i], "-")
+		if len(parts) == 2 {
+			parts = append([]string{"*"}, parts...)
+		}
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("expected date (Y-M-D or M-D), got %q in %q", tokens[i], s)
+		}
+		if spec.Year, err = handleCalendarComponent(s, parts[0], "year", 1970, 9999); err != nil {
+			return nil, err
+		}
+		if spec.Month, err = handleCalendarComponent(s, parts[1], "month", 1, 12); err != nil {
+			return nil, err
+		}
+		if spec.Day, err = handleCalendarComponent(s, parts[2], "day", 1, 31); err != nil {
+			return nil, err
+		}
+		i++
+	}
+
+	// parse (optional) time, defaulting to midnight
+	timeParts := []string{"00", "00", "00"}
+	if i < len(tokens) && strings.IndexByte(tokens[i], ':') >= 0 {
+		timeParts = strings.Split(tokens[i], ":")
+		if len(timeParts) == 2 {
+			timeParts = append(timeParts, "00")
+		}
+		if len(timeParts) != 3 {
+			return nil, fmt.Errorf("expected time (H:M:S or H:M), got %q in %q", tokens[i], s)
+		}
+		i++
+	}
+	if spec.Hour, err = handleCalendarComponent(s, timeParts[0], "hour", 0, 23); err != nil {
+		return nil, err
+	}
+	if spec.Minute, err = handleCalendarComponent(s, timeParts[1], "minute", 0, 59); err != nil {
+		return nil, err
+	}
+	if spec.Second, err = handleCalendarComponent(s, timeParts[2], "second", 0, 59); err != nil {
+		return nil, err
+	}
+
+	// parse (optional) timezone
+	if i < len(tokens) {
+		loc, j, err := handleTimezone(tokens[i], 0, &ParseTimestampOptions{})
+		if err != nil || j != len(tokens[i]) {
+			return nil, fmt.Errorf("expected timezone, got %q in %q", tokens[i], s)
+		}
+		spec.Location = loc
+		i++
+	}
+
+	if i < len(tokens) {
+		return nil, fmt.Errorf("expected end of input, got %q in %q", strings.Join(tokens[i:], " "), s)
+	}
+
+	return spec, nil
+}
+
+// Next returns the first time after the given time that matches the calendar event,
+// and whether there is one. Times are evaluated in c.Location, or the location of
+// after if nil. Wall clock times skipped by a DST change do not match.
+func (c *CalendarSpec) Next(after time.Time) (time.Time, bool) {
+	loc := c.Location
+	if loc == nil {
+		loc = after.Location()
+	}
+
+	// start at the next full second
+	t := after.In(loc).Truncate(Second).Add(Second)
+	for t.Year() <= 9999 {
+		year, month, day := t.Date()
+		hour, minute, second := t.Clock()
+		var next time.Time
+		var step time.Duration // absolute time to the next hour, minute, or second
+		switch {
+		case !matchCalendar(c.Year, year):
+			next = time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc)
+		case !matchCalendar(c.Month, int(month)):
+			next = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+		case !matchCalendar(c.Day, day) || !c.matchWeekday(t.Weekday()):
+			next = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case !matchCalendar(c.Hour, hour):
+			next = time.Date(year, month, day, hour+1, 0, 0, 0, loc)
+			step = Hour - time.Duration(minute)*Minute - time.Duration(second)*Second
+		case !matchCalendar(c.Minute, minute):
+			next = time.Date(year, month, day, hour, minute+1, 0, 0, loc)
+			step = Minute - time.Duration(second)*Second
+		case !matchCalendar(c.Second, second):
+			next = time.Date(year, month, day, hour, minute, second+1, 0, loc)
+			step = Second
+		default:
+			return t, true
+		}
+
+		// time.Date picks the first occurrence of a wall clock time repeated by a DST
+		// change, which is before t in the second occurrence, so advance in absolute time
+		if !next.After(t) {
+			if step == 0 {
+				step = Second
+			}
+			next = t.Add(step)
+		}
+		t = next
+	}
+
+	return time.Time{}, false
+}
+
+// matchWeekday reports whether wd is in c.Weekdays, or c.Weekdays is empty.
+func (c *CalendarSpec) matchWeekday(wd time.Weekday) bool {
+	if len(c.Weekdays) == 0 {
+		return true
+	}
+	for _, w := range c.Weekdays {
+		if w == wd {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime_test
+
+import (
+	"testing"
+	"time"
+
+	systemdtime "gitlab.com/allddd/go-systemd-time"
+)
+
+func TestParseCalendar(t *testing.T) {
+	cases := []struct {
+		input     string
+		after     time.Time
+		expect    time.Time
+		expectErr bool
+	}{
+		// shorthands
+		{"minutely", time.Date(2009, 11, 10, 23, 0, 30, 0, time.UTC), time.Date(2009, 11, 10, 23, 1, 0, 0, time.UTC), false},
+		{"hourly", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"daily", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"weekly", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 16, 0, 0, 0, 0, time.UTC), false},
+		{"monthly", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 12, 1, 0, 0, 0, 0, time.UTC), false},
+		{"yearly", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"annually", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"quarterly", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"semiannually", time.Date(2009, 5, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 7, 1, 0, 0, 0, 0, time.UTC), false},
+		// full form
+		{"*-*-* 02:00:00", time.Date(2009, 11, 10, 1, 0, 0, 0, time.UTC), time.Date(2009, 11, 10, 2, 0, 0, 0, time.UTC), false},
+		{"*-*-* 02:00:00", time.Date(2009, 11, 10, 2, 0, 0, 0, time.UTC), time.Date(2009, 11, 11, 2, 0, 0, 0, time.UTC), false},
+		{"Mon,Fri *-*-* 00:00:00", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 13, 0, 0, 0, 0, time.UTC), false},
+		{"Monday,Friday *-*-* 00:00:00", time.Date(2009, 11, 13, 0, 0, 0, 0, time.UTC), time.Date(2009, 11, 16, 0, 0, 0, 0, time.UTC), false},
+		{"*-*-01 12:00", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 12, 1, 12, 0, 0, 0, time.UTC), false},
+		{"*-*-31 00:00:00", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{"02-29", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2012, 2, 29, 0, 0, 0, 0, time.UTC), false},
+		{"*-*-* *:00,30:00", time.Date(2009, 11, 10, 23, 10, 0, 0, time.UTC), time.Date(2009, 11, 10, 23, 30, 0, 0, time.UTC), false},
+		{"2009-11-10 18:15:22", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"2009-11-10 18:15:22", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), time.Time{}, false},
+		{"Sat 08:30", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 14, 8, 30, 0, 0, time.UTC), false},
+		{"Sat 08:30 UTC", time.Date(2009, 11, 10, 23, 0, 0, 0, time.FixedZone("", 3600)), time.Date(2009, 11, 14, 8, 30, 0, 0, time.UTC), false},
+		{"  daily  ", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"daily UTC", time.Date(2009, 11, 10, 23, 0, 0, 0, time.FixedZone("", 3600)), time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"weekly Asia/Tokyo", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 16, 0, 0, 0, 0, tzTokyo), false},
+		// steps
+		{"*:0/15", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 10, 23, 15, 0, 0, time.UTC), false},
+		{"*:0/15", time.Date(2009, 11, 10, 23, 45, 0, 0, time.UTC), time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"*:/15", time.Date(2009, 11, 10, 23, 31, 0, 0, time.UTC), time.Date(2009, 11, 10, 23, 45, 0, 0, time.UTC), false},
+		{"*:*/15", time.Date(2009, 11, 10, 23, 31, 0, 0, time.UTC), time.Date(2009, 11, 10, 23, 45, 0, 0, time.UTC), false},
+		{"*:0/25", time.Date(2009, 11, 10, 23, 50, 0, 0, time.UTC), time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"*:5/25", time.Date(2009, 11, 10, 23, 30, 0, 0, time.UTC), time.Date(2009, 11, 10, 23, 55, 0, 0, time.UTC), false},
+		{"*:0,30/10", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 10, 23, 30, 0, 0, time.UTC), false},
+		{"*:0,30/10", time.Date(2009, 11, 10, 23, 50, 0, 0, time.UTC), time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"*-*-1/2 00:00:00", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"*-*-1/2 00:00:00", time.Date(2009, 11, 29, 0, 0, 0, 0, time.UTC), time.Date(2009, 12, 1, 0, 0, 0, 0, time.UTC), false},
+		{"*-*-/2 00:00:00", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		// ranges
+		{"Mon..Fri *-*-* 22:00:00", time.Date(2009, 11, 13, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 16, 22, 0, 0, 0, time.UTC), false},
+		{"Mon..Fri *-*-* 22:00:00", time.Date(2009, 11, 10, 21, 0, 0, 0, time.UTC), time.Date(2009, 11, 10, 22, 0, 0, 0, time.UTC), false},
+		{"Sat..Sun 10:00", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 14, 10, 0, 0, 0, time.UTC), false},
+		{"Sat..Mon 10:00", time.Date(2009, 11, 15, 11, 0, 0, 0, time.UTC), time.Date(2009, 11, 16, 10, 0, 0, 0, time.UTC), false},
+		{"Mon,Wed..Thu 10:00", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 11, 10, 0, 0, 0, time.UTC), false},
+		{"*-*-1..7 00:00:00", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 12, 1, 0, 0, 0, 0, time.UTC), false},
+		{"*-*-1..7 00:00:00", time.Date(2009, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2009, 12, 2, 0, 0, 0, 0, time.UTC), false},
+		{"*-*-1..20/3 00:00:00", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 13, 0, 0, 0, 0, time.UTC), false},
+		{"*-*-1..20/3 00:00:00", time.Date(2009, 11, 19, 0, 0, 0, 0, time.UTC), time.Date(2009, 12, 1, 0, 0, 0, 0, time.UTC), false},
+		{"*-*-* 9..17,20:00", time.Date(2009, 11, 10, 17, 30, 0, 0, time.UTC), time.Date(2009, 11, 10, 20, 0, 0, 0, time.UTC), false},
+		// errors
+		{"", time.Time{}, time.Time{}, true},
+		{"\u00a0", time.Time{}, time.Time{}, true},
+		{"daily 12:00", time.Time{}, time.Time{}, true},
+		{"daily UTC x", time.Time{}, time.Time{}, true},
+		{"Mon,", time.Time{}, time.Time{}, true},
+		{"Foo *-*-*", time.Time{}, time.Time{}, true},
+		{"*-13-* 00:00:00", time.Time{}, time.Time{}, true},
+		{"*-*-32 00:00:00", time.Time{}, time.Time{}, true},
+		{"*-*-* 24:00:00", time.Time{}, time.Time{}, true},
+		{"*-*-* 00:60:00", time.Time{}, time.Time{}, true},
+		{"*-*-*-* 00:00:00", time.Time{}, time.Time{}, true},
+		{"*-*-* 00", time.Time{}, time.Time{}, true},
+		{"*-*-* 00:00:00:00", time.Time{}, time.Time{}, true},
+		{"*-x-* 00:00:00", time.Time{}, time.Time{}, true},
+		{"*-*-* 00:00:00 Mars/Olympus", time.Time{}, time.Time{}, true},
+		{"*-*-* 00:00:00 UTC x", time.Time{}, time.Time{}, true},
+		{"*:0/0", time.Time{}, time.Time{}, true},
+		{"*:0/", time.Time{}, time.Time{}, true},
+		{"*:0/x", time.Time{}, time.Time{}, true},
+		{"*:60/5", time.Time{}, time.Time{}, true},
+		{"*-*-7..1 00:00:00", time.Time{}, time.Time{}, true},
+		{"*-*-1..32 00:00:00", time.Time{}, time.Time{}, true},
+		{"*-*-1.. 00:00:00", time.Time{}, time.Time{}, true},
+		{"Mon.. 00:00:00", time.Time{}, time.Time{}, true},
+		{"Mon..Foo 00:00:00", time.Time{}, time.Time{}, true},
+	}
+	for _, tc := range cases {
+		spec, err := systemdtime.ParseCalendar(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		got, ok := spec.Next(tc.after)
+		if ok != !tc.expect.IsZero() {
+			t.Errorf("%q: expected ok %v, got %v", tc.input, !tc.expect.IsZero(), ok)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q after %v: expected %v, got %v", tc.input, tc.after, tc.expect, got)
+		}
+	}
+}
+
+func TestCalendarSpecNextLocation(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	spec, err := systemdtime.ParseCalendar("*-*-* 02:30:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 02:30 does not exist on the day of the DST change
+	got, ok := spec.Next(time.Date(2009, 3, 28, 12, 0, 0, 0, loc))
+	expect := time.Date(2009, 3, 30, 2, 30, 0, 0, loc)
+	if !ok || !got.Equal(expect) {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+
+	// wall clock times repeated by a DST change match in both occurrences
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	spec, err = systemdtime.ParseCalendar("*-*-* 01:45:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	edt := time.Date(2009, 11, 1, 5, 30, 0, 0, time.UTC) // 01:30 EDT
+	est := time.Date(2009, 11, 1, 6, 30, 0, 0, time.UTC) // 01:30 EST
+	for _, tc := range []struct{ after, expect time.Time }{
+		{edt.In(ny), edt.Add(15 * time.Minute)},
+		{est.In(ny), est.Add(15 * time.Minute)},
+		{est.Add(20 * time.Minute).In(ny), time.Date(2009, 11, 2, 1, 45, 0, 0, ny)},
+	} {
+		got, ok = spec.Next(tc.after)
+		if !ok || !got.Equal(tc.expect) || !got.After(tc.after) {
+			t.Errorf("after %v: expected %v, got %v", tc.after, tc.expect, got)
+		}
+	}
+
+	// times are evaluated in the timezone of the event, if given
+	spec, err = systemdtime.ParseCalendar("*-*-* 02:30:00 Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok = spec.Next(time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC))
+	expect = time.Date(2009, 11, 10, 2, 30, 0, 0, loc)
+	if !ok || !got.Equal(expect) || got.Location().String() != "Europe/Berlin" {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+}
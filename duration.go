@@ -0,0 +1,104 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration is a time.Duration that is encoded as a time span string, e.g. to use
+// values like "30min" in JSON configuration files.
+type Duration time.Duration
+
+// String returns d as a time span string as returned by FormatTimespan.
+func (d Duration) String() string {
+	return FormatTimespan(time.Duration(d))
+}
+
+// Set parses a time span string as accepted by ParseTimespan into d. Together with
+// String, this implements flag.Value, e.g. for "-timeout=2h30min":
+//
+//	var timeout systemdtime.Duration
+//	flag.Var(&timeout, "timeout", "time span to wait")
+func (d *Duration) Set(s string) error {
+	v, err := ParseTimespan(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Get returns d as a time.Duration, implementing flag.Getter.
+func (d *Duration) Get() interface{} {
+	return time.Duration(*d)
+}
+
+// MarshalJSON encodes d as a time span string as returned by FormatTimespan, e.g.
+// "1h 30min".
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(FormatTimespan(time.Duration(d)))
+}
+
+// UnmarshalJSON decodes a time span string as accepted by ParseTimespan, or a number
+// of nanoseconds, into d. null leaves d unchanged.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		v, err := ParseTimespan(s)
+		if err != nil {
+			return err
+		}
+		*d = Duration(v)
+		return nil
+	}
+
+	var n int64
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("expected time span or number of nanoseconds, got %s: %w", b, err)
+	}
+	*d = Duration(n)
+	return nil
+}
+
+// MarshalText encodes d as a time span string as returned by FormatTimespan,
+// implementing encoding.TextMarshaler for formats such as YAML and TOML.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(FormatTimespan(time.Duration(d))), nil
+}
+
+// UnmarshalText decodes a time span string as accepted by ParseTimespan into d,
+// implementing encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(b []byte) error {
+	return d.Set(string(b))
+}
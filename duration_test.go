@@ -0,0 +1,188 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime_test
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	systemdtime "gitlab.com/allddd/go-systemd-time"
+)
+
+func TestDurationJSON(t *testing.T) {
+	type config struct {
+		Timeout systemdtime.Duration `json:"timeout"`
+	}
+
+	// decode
+	cases := []struct {
+		input     string
+		expect    time.Duration
+		expectErr bool
+	}{
+		{`{"timeout": "30min"}`, 30 * systemdtime.Minute, false},
+		{`{"timeout": "1h 30min"}`, 90 * systemdtime.Minute, false},
+		{`{"timeout": "infinity"}`, systemdtime.Infinity, false},
+		{`{"timeout": 1500000000}`, 1500 * systemdtime.Millisecond, false},
+		{`{"timeout": -1000}`, -1000 * systemdtime.Nanosecond, false},
+		{`{"timeout": null}`, 0, false},
+		{`{"timeout": "5 parsecs"}`, 0, true},
+		{`{"timeout": ""}`, 0, true},
+		{`{"timeout": 1.5}`, 0, true},
+		{`{"timeout": true}`, 0, true},
+	}
+	for _, tc := range cases {
+		var c config
+		err := json.Unmarshal([]byte(tc.input), &c)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if time.Duration(c.Timeout) != tc.expect {
+			t.Errorf("%s: expected %v, got %v", tc.input, tc.expect, time.Duration(c.Timeout))
+		}
+	}
+
+	// parse errors are passed through
+	var c config
+	err := json.Unmarshal([]byte(`{"timeout": "   "}`), &c)
+	if !errors.Is(err, systemdtime.ErrEmptyInput) {
+		t.Errorf("expected ErrEmptyInput, got %v", err)
+	}
+
+	// encode and round trip
+	for _, d := range []time.Duration{0, 30 * systemdtime.Minute, systemdtime.Year + 1, systemdtime.Infinity, -systemdtime.Hour, math.MinInt64} {
+		b, err := json.Marshal(config{Timeout: systemdtime.Duration(d)})
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", d, err)
+			continue
+		}
+		var got config
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Errorf("%s: unexpected error: %v", b, err)
+			continue
+		}
+		if time.Duration(got.Timeout) != d {
+			t.Errorf("%s: expected %v after round trip, got %v", b, d, time.Duration(got.Timeout))
+		}
+	}
+
+	b, err := json.Marshal(config{Timeout: systemdtime.Duration(90 * systemdtime.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := `{"timeout":"1h 30min"}`; string(b) != expect {
+		t.Errorf("expected %s, got %s", expect, b)
+	}
+	b, err = json.Marshal(config{Timeout: systemdtime.Duration(math.MinInt64)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := `{"timeout":"-292y 3M 1w 16h 17min 16s 854ms 775us 808ns"}`; string(b) != expect {
+		t.Errorf("expected %s, got %s", expect, b)
+	}
+	b, err = json.Marshal(config{Timeout: systemdtime.Duration(-90 * systemdtime.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := `{"timeout":"-1h 30min"}`; string(b) != expect {
+		t.Errorf("expected %s, got %s", expect, b)
+	}
+}
+
+func TestDurationText(t *testing.T) {
+	var _ encoding.TextMarshaler = systemdtime.Duration(0)
+	var _ encoding.TextUnmarshaler = (*systemdtime.Duration)(nil)
+
+	// encode and round trip
+	for _, d := range []time.Duration{0, 30 * systemdtime.Minute, 90 * systemdtime.Minute, systemdtime.Year + 1, systemdtime.Infinity, -systemdtime.Hour, math.MinInt64} {
+		b, err := systemdtime.Duration(d).MarshalText()
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", d, err)
+			continue
+		}
+		if expect := systemdtime.FormatTimespan(d); string(b) != expect {
+			t.Errorf("%v: expected %q, got %q", d, expect, b)
+		}
+		var got systemdtime.Duration
+		if err := got.UnmarshalText(b); err != nil {
+			t.Errorf("%q: unexpected error: %v", b, err)
+			continue
+		}
+		if time.Duration(got) != d {
+			t.Errorf("%q: expected %v after round trip, got %v", b, d, time.Duration(got))
+		}
+	}
+
+	// decode
+	var d systemdtime.Duration
+	for _, input := range []string{"", "5 parsecs", "-"} {
+		if err := d.UnmarshalText([]byte(input)); err == nil {
+			t.Errorf("%q: expected error, got nil", input)
+		}
+	}
+	if err := d.UnmarshalText([]byte("   ")); !errors.Is(err, systemdtime.ErrEmptyInput) {
+		t.Errorf("expected ErrEmptyInput, got %v", err)
+	}
+}
+
+func TestDurationFlag(t *testing.T) {
+	var timeout systemdtime.Duration
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&timeout, "timeout", "time span to wait")
+
+	if err := fs.Parse([]string{"-timeout=2h30min"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := 150 * systemdtime.Minute; time.Duration(timeout) != expect {
+		t.Errorf("expected %v, got %v", expect, time.Duration(timeout))
+	}
+	if got := fs.Lookup("timeout").Value.String(); got != "2h 30min" {
+		t.Errorf("expected %q, got %q", "2h 30min", got)
+	}
+	getter, ok := fs.Lookup("timeout").Value.(flag.Getter)
+	if !ok {
+		t.Fatalf("expected flag.Getter")
+	}
+	if got, ok := getter.Get().(time.Duration); !ok || got != 150*systemdtime.Minute {
+		t.Errorf("expected %v, got %v", 150*systemdtime.Minute, getter.Get())
+	}
+
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse([]string{"-timeout=5 parsecs"}); err == nil {
+		t.Errorf("%q: expected error, got nil", "5 parsecs")
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime
+
+import (
+	"strings"
+	"time"
+)
+
+// japaneseEras lists the modern Japanese eras, oldest first, with the Gregorian date
+// of their first day. Japan adopted the Gregorian calendar only on Meiji 6-01-01
+// (gregorianAdoption), so earlier Meiji dates are lunisolar.
+var japaneseEras = []struct {
+	name  string
+	kanji string
+	start time.Time
+}{
+	{"Meiji", "明治", time.Date(1868, time.October, 23, 0, 0, 0, 0, time.UTC)},
+	{"Taisho", "大正", time.Date(1912, time.July, 30, 0, 0, 0, 0, time.UTC)},
+	{"Showa", "昭和", time.Date(1926, time.December, 25, 0, 0, 0, 0, time.UTC)},
+	{"Heisei", "平成", time.Date(1989, time.January, 8, 0, 0, 0, 0, time.UTC)},
+	{"Reiwa", "令和", time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC)},
+}
+
+// gregorianAdoption is the first day of the Gregorian calendar in Japan (Meiji 6).
+var gregorianAdoption = time.Date(1873, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ParseJapaneseEra parses a date in the Japanese era calendar and returns the time.
+//
+// Dates are specified as an era name followed by Y-MM-DD, where Y is the year of the
+// era (starting at 1). Era names are Meiji, Taisho, Showa, Heisei, and Reiwa, either
+// romanized (case-insensitive) or in kanji (e.g. "令和"). A year alone may be given
+// as "Y年", or "元年" for year 1, and refers to the first day of that year within the
+// era. The date must fall within the era, e.g. Heisei 31 ends on 04-30 and Reiwa 1
+// starts on 05-01. Dates before Meiji 6-01-01, when Japan adopted the Gregorian
+// calendar, are rejected. The result is 00:00:00 UTC of that day.
+//
+// Examples for valid dates:
+//
+//	Reiwa 1-11-10
+//	Heisei 31-04-30
+//	showa 64-01-07
+//	令和1-11-10
+//	令和元年
+//	平成21年
+func ParseJapaneseEra(s string) (time.Time, error) {
+	if isBlank(s) {
+		return time.Time{}, parseErrorf(s, 0, "expected Japanese era date, got %q: %w", s, ErrEmptyInput)
+	}
+
+	// parse era, kanji names may be directly followed by the year (e.g. "令和元年")
+	era := -1
+	i := 0
+	for j, e := range japaneseEras {
+		if strings.HasPrefix(s, e.kanji) {
+			era, i = j, len(e.kanji)
+			break
+		}
+	}
+	if era < 0 {
+		var name string
+		name, i = readWord(s, 0)
+		for j, e := range japaneseEras {
+			if strings.EqualFold(name, e.name) {
+				era = j
+				break
+			}
+		}
+		if era < 0 {
+			return time.Time{}, parseErrorf(s, 0, "expected Meiji, Taisho, Showa, Heisei, or Reiwa, got %q in %q", name, s)
+		}
+	}
+	for i < len(s) && s[i] == ' ' {
+		i++
+	}
+
+	// parse year alone ("元年" or "Y年"), or date
+	dateStart := i
+	yearOnly := false
+	year, month, day := 1, 1, 1
+	if strings.HasPrefix(s[i:], "元年") {
+		yearOnly, i = true, i+len("元年")
+	} else if n, j, err := readNum(s, i); err == nil && strings.HasPrefix(s[j:], "年") {
+		yearOnly, year, i = true, n, j+len("年")
+	}
+	if !yearOnly {
+		// the year is 2-digit so undo the century mapping of handleDate
+		var fullYear bool
+		var err error
+		year, month, day, i, fullYear, err = handleDate(s, i, defaultTwoDigitYearPivot)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if fullYear {
+			year = 0
+		}
+		year %= 100
+	}
+	if year < 1 || year > 99 {
+		return time.Time{}, parseErrorf(s, dateStart, "expected era year in range 1-99, got %q", s)
+	}
+	if i < len(s) {
+		return time.Time{}, parseErrorf(s, i, "expected end of input, got %q in %q", s[i:], s)
+	}
+
+	start := japaneseEras[era].start
+	t := time.Date(start.Year()+year-1, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if yearOnly && t.Before(start) {
+		t = start // year 1 starts with the era
+	}
+	if t.Before(gregorianAdoption) {
+		return time.Time{}, parseErrorf(s, dateStart, "expected date on or after %s (Gregorian calendar), got %q",
+			gregorianAdoption.Format("2006-01-02"), s)
+	}
+	if t.Before(start) {
+		return time.Time{}, parseErrorf(s, dateStart, "expected date on or after %s for %s, got %q",
+			start.Format("2006-01-02"), japaneseEras[era].name, s)
+	}
+	if era+1 < len(japaneseEras) && !t.Before(japaneseEras[era+1].start) {
+		return time.Time{}, parseErrorf(s, dateStart, "expected date before %s for %s, got %q",
+			japaneseEras[era+1].start.Format("2006-01-02"), japaneseEras[era].name, s)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime_test
+
+import (
+	"testing"
+	"time"
+
+	systemdtime "gitlab.com/allddd/go-systemd-time"
+)
+
+func TestParseJapaneseEra(t *testing.T) {
+	cases := []struct {
+		input     string
+		expect    time.Time
+		expectErr bool
+	}{
+		{"Reiwa 1-11-10", time.Date(2019, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"Reiwa 1-05-01", time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), false},
+		{"Heisei 31-04-30", time.Date(2019, 4, 30, 0, 0, 0, 0, time.UTC), false},
+		{"Heisei 21-11-10", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"Heisei 1-01-08", time.Date(1989, 1, 8, 0, 0, 0, 0, time.UTC), false},
+		{"showa 64-01-07", time.Date(1989, 1, 7, 0, 0, 0, 0, time.UTC), false},
+		{"SHOWA 1-12-25", time.Date(1926, 12, 25, 0, 0, 0, 0, time.UTC), false},
+		{"Taisho 15-12-24", time.Date(1926, 12, 24, 0, 0, 0, 0, time.UTC), false},
+		{"Meiji 45-07-29", time.Date(1912, 7, 29, 0, 0, 0, 0, time.UTC), false},
+		{"令和1-11-10", time.Date(2019, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"平成 31-04-30", time.Date(2019, 4, 30, 0, 0, 0, 0, time.UTC), false},
+		{"令和元年", time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), false},
+		{"令和 元年", time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), false},
+		{"令和2年", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"平成21年", time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"平成31年", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"Heisei 21年", time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"Meiji 6-01-01", time.Date(1873, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		// outside of era
+		{"Reiwa 1-04-30", time.Time{}, true},
+		{"Heisei 31-05-01", time.Time{}, true},
+		{"Showa 64-01-08", time.Time{}, true},
+		{"Meiji 1-01-01", time.Time{}, true},
+		{"平成32年", time.Time{}, true},
+		{"明治元年", time.Time{}, true},
+		{"Meiji 5-12-31", time.Time{}, true},
+		{"Meiji 1-10-23", time.Time{}, true},
+		// invalid
+		{"Reiwa 0-11-10", time.Time{}, true},
+		{"令和0年", time.Time{}, true},
+		{"令和100年", time.Time{}, true},
+		{"令和元年-11-10", time.Time{}, true},
+		{"令和元", time.Time{}, true},
+		{"Reiwa 2019-11-10", time.Time{}, true},
+		{"Edo 1-01-01", time.Time{}, true},
+		{"Reiwa", time.Time{}, true},
+		{"Reiwa 1-11-10 UTC", time.Time{}, true},
+		{"1-11-10", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseJapaneseEra(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// evalValue is an intermediate value of EvalTimespan, either a time span or a scalar.
+type evalValue struct {
+	d      time.Duration
+	n      float64
+	scalar bool
+}
+
+// EvalTimespan evaluates an arithmetic expression over time spans and returns the
+// resulting duration, e.g. "(1h 30min) * 2" is 3h.
+//
+// Operands are time spans as accepted by ParseTimespan (e.g. "1h 30min") and scalars
+// (e.g. "2" or "1.5"). Note that a bare number is a scalar, not seconds as in
+// ParseTimespan. The operators are "+" and "-" between time spans, "*" between a time
+// span and a scalar, "/" of a time span by a scalar or by another time span (giving a
+// scalar), unary "+" and "-", and parentheses, with the usual precedence. Division of
+// a time span by an integer truncates toward zero. The result must be a time span.
+//
+// Examples for valid expressions:
+//
+//	(1h 30min) * 2
+//	2h / 4
+//	1d - 2h 30min
+//	-(5min + 30s)
+//	1h * (1d / 8h)
+func EvalTimespan(s string) (time.Duration, error) {
+	if isBlank(s) {
+		return 0, parseErrorf(s, 0, "expected expression, got %q: %w", s, ErrEmptyInput)
+	}
+
+	v, i, err := evalSum(s, 0)
+	if err != nil {
+		return 0, err
+	}
+	if i < len(s) {
+		return 0, parseErrorf(s, i, "expected operator, got %q in %q", s[i:], s)
+	}
+	if v.scalar {
+		return 0, parseErrorf(s, 0, "expected time span as result, got scalar %g in %q", v.n, s)
+	}
+	return v.d, nil
+}
+
+// skipSpaces returns the position of the first non-space character in s at or after
+// pos.
+func skipSpaces(s string, pos int) int {
+	for pos < len(s) && s[pos] == ' ' {
+		pos++
+	}
+	return pos
+}
+
+// evalSum evaluates a sum ("a + b - c") from s starting at position pos and returns
+// the value, position after the sum, and any error.
+func evalSum(s string, pos int) (evalValue, int, error) {
+	v, i, err := evalProduct(s, pos)
+	if err != nil {
+		return evalValue{}, pos, err
+	}
+	for i < len(s) && (s[i] == '+' || s[i] == '-') {
+		op := i
+		w, j, err := evalProduct(s, i+1)
+		if err != nil {
+			return evalValue{}, pos, err
+		}
+		if v.scalar || w.scalar {
+			return evalValue{}, pos, parseErrorf(s, op, "expected time spans around %q, got scalar in %q", string(s[op]), s)
+		}
+		if s[op] == '-' {
+			if w.d == math.MinInt64 {
+				return evalValue{}, pos, parseErrorf(s, op, "time span out of range in %q", s)
+			}
+			w.d = -w.d
+		}
+		if (w.d > 0 && v.d > math.MaxInt64-w.d) || (w.d < 0 && v.d < math.MinInt64-w.d) {
+			return evalValue{}, pos, parseErrorf(s, op, "time span out of range in %q", s)
+		}
+		v.d += w.d
+		i = j
+	}
+	return v, i, nil
+}
+
+// evalProduct evaluates a product ("a * b / c") from s starting at position pos and
+// returns the value, position after the product, and any error.
+func evalProduct(s string, pos int) (evalValue, int, error) {
+	v, i, err := evalUnary(s, pos)
+	if err != nil {
+		return evalValue{}, pos, err
+	}
+	for i < len(s) && (s[i] == '*' || s[i] == '/') {
+		op := i
+		w, j, err := evalUnary(s, i+1)
+		if err != nil {
+			return evalValue{}, pos, err
+		}
+		var ok bool
+		switch {
+		case s[op] == '*' && !v.scalar && !w.scalar:
+			return evalValue{}, pos, parseErrorf(s, op, "expected scalar operand of '*', got two time spans in %q", s)
+		case s[op] == '*' && v.scalar && w.scalar:
+			v.n *= w.n
+			ok = true
+		case s[op] == '*' && v.scalar:
+			v, ok = evalValue{d: mulDuration(w.d, v.n)}, fitsDuration(float64(w.d)*v.n)
+		case s[op] == '*':
+			v.d, ok = mulDuration(v.d, w.n), fitsDuration(float64(v.d)*w.n)
+		case w.scalar && w.n == 0, !w.scalar && w.d == 0:
+			return evalValue{}, pos, parseErrorf(s, op, "division by zero in %q", s)
+		case v.scalar && !w.scalar:
+			return evalValue{}, pos, parseErrorf(s, op, "expected time span or scalar divided by scalar, got scalar divided by time span in %q", s)
+		case v.scalar:
+			v.n /= w.n
+			ok = true
+		case !w.scalar:
+			v = evalValue{n: float64(v.d) / float64(w.d), scalar: true}
+			ok = true
+		case w.n == math.Trunc(w.n) && math.Abs(w.n) < 1<<53:
+			ok = !(v.d == math.MinInt64 && w.n == -1)
+			if ok {
+				v.d /= time.Duration(w.n)
+			}
+		default:
+			v.d, ok = mulDuration(v.d, 1/w.n), fitsDuration(float64(v.d)/w.n)
+		}
+		if !ok {
+			return evalValue{}, pos, parseErrorf(s, op, "time span out of range in %q", s)
+		}
+		i = j
+	}
+	return v, i, nil
+}
+
+// mulDuration returns d multiplied by n, exactly if n is an integer. The result is
+// only meaningful if fitsDuration reports that the product is in range.
+func mulDuration(d time.Duration, n float64) time.Duration {
+	if n == math.Trunc(n) && math.Abs(n) < 1<<53 {
+		return d * time.Duration(n)
+	}
+	return time.Duration(math.Round(float64(d) * n))
+}
+
+// fitsDuration reports whether f is in the range of time.Duration.
+func fitsDuration(f float64) bool {
+	return f > math.MinInt64 && f < math.MaxInt64
+}
+
+// evalUnary evaluates an operand with optional unary sign ("-a") from s starting at
+// position pos and returns the value, position after the operand, and any error.
+func evalUnary(s string, pos int) (evalValue, int, error) {
+	i := skipSpaces(s, pos)
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		v, j, err := evalUnary(s, i+1)
+		if err != nil {
+			return evalValue{}, pos, err
+		}
+		if s[i] == '-' {
+			if v.d == math.MinInt64 {
+				return evalValue{}, pos, parseErrorf(s, i, "time span out of range in %q", s)
+			}
+			v.d, v.n = -v.d, -v.n
+		}
+		return v, j, nil
+	}
+	return evalOperand(s, i)
+}
+
+// evalOperand evaluates a parenthesized expression, time span, or scalar from s
+// starting at position pos and returns the value, position after the operand
+// (including trailing spaces), and any error.
+func evalOperand(s string, pos int) (evalValue, int, error) {
+	if pos < len(s) && s[pos] == '(' {
+		v, i, err := evalSum(s, pos+1)
+		if err != nil {
+			return evalValue{}, pos, err
+		}
+		if i >= len(s) || s[i] != ')' {
+			return evalValue{}, pos, parseErrorf(s, i, "expected ')' to match '(' at position %d in %q", pos, s)
+		}
+		return v, skipSpaces(s, i+1), nil
+	}
+
+	// operands extend to the next operator or parenthesis
+	end := pos
+	for end < len(s) && strings.IndexByte("+-*/()", s[end]) < 0 {
+		end++
+	}
+	lit := strings.TrimRight(s[pos:end], " ")
+	if lit == "" {
+		return evalValue{}, pos, parseErrorf(s, pos, "expected time span or scalar in %q", s)
+	}
+
+	// a number without unit is a scalar
+	if strings.Trim(lit, "0123456789.") == "" && strings.Count(lit, ".") <= 1 && lit != "." {
+		n, err := strconv.ParseFloat(lit, 64)
+		if err != nil {
+			return evalValue{}, pos, parseErrorf(s, pos, "expected scalar, got %q in %q: %w", lit, s, err)
+		}
+		return evalValue{n: n, scalar: true}, end, nil
+	}
+
+	d, err := ParseTimespan(lit)
+	if err != nil {
+		return evalValue{}, pos, shiftParseError(err, s, pos)
+	}
+	return evalValue{d: d}, end, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	systemdtime "gitlab.com/allddd/go-systemd-time"
+)
+
+func TestEvalTimespan(t *testing.T) {
+	cases := []struct {
+		input     string
+		expect    time.Duration
+		expectErr bool
+	}{
+		{"(1h 30m) * 2", 3 * systemdtime.Hour, false},
+		{"2h / 4", 30 * systemdtime.Minute, false},
+		{"2 * 1h 30min", 3 * systemdtime.Hour, false},
+		{"1h 30min", 90 * systemdtime.Minute, false},
+		{"1d - 2h 30min", 21*systemdtime.Hour + 30*systemdtime.Minute, false},
+		{"1h + 30min * 2", 2 * systemdtime.Hour, false},
+		{"(1h + 30min) * 2", 3 * systemdtime.Hour, false},
+		{"-(5min + 30s)", -5*systemdtime.Minute - 30*systemdtime.Second, false},
+		{"-5min + 10min", 5 * systemdtime.Minute, false},
+		{"1h * 1.5", 90 * systemdtime.Minute, false},
+		{"1h / 0.5", 2 * systemdtime.Hour, false},
+		{"1h * (1d / 8h)", 3 * systemdtime.Hour, false},
+		{"1h * (2 * 3)", 6 * systemdtime.Hour, false},
+		{"1s / 3", 333333333 * systemdtime.Nanosecond, false},
+		{"((1h))", systemdtime.Hour, false},
+		{"  1h  *  2  ", 2 * systemdtime.Hour, false},
+		{"1h*-2", -2 * systemdtime.Hour, false},
+		{"2h / 0", 0, true},
+		{"2h / 0s", 0, true},
+		{"2h / (1h - 1h)", 0, true},
+		{"1h * 1h", 0, true},
+		{"2 / 1h", 0, true},
+		{"1h + 2", 0, true},
+		{"2 * 3", 0, true},
+		{"30", 0, true},
+		{"(1h", 0, true},
+		{"1h)", 0, true},
+		{"()", 0, true},
+		{"1h +", 0, true},
+		{"* 1h", 0, true},
+		{"5 parsecs * 2", 0, true},
+		{"1.2.3 * 1h", 0, true},
+		{"infinity + 1s", 0, true},
+		{"300y * 2", 0, true},
+		{"-300y - 300y", 0, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.EvalTimespan(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got %v", tc.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expect {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	if _, err := systemdtime.EvalTimespan("  "); !errors.Is(err, systemdtime.ErrEmptyInput) {
+		t.Errorf("expected ErrEmptyInput, got %v", err)
+	}
+
+	// errors in time spans refer to the whole expression
+	_, err := systemdtime.EvalTimespan("2 * (1h 5 parsecs)")
+	var pe *systemdtime.ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+	if pe.Input != "2 * (1h 5 parsecs)" || pe.Pos < 5 {
+		t.Errorf("expected error in whole expression, got input %q pos %d", pe.Input, pe.Pos)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timespanUnits lists the units used by FormatTimespan, greatest first.
+var timespanUnits = []struct {
+	name string
+	unit time.Duration
+}{
+	{"y", Year},
+	{"M", Month},
+	{"w", Week},
+	{"d", Day},
+	{"h", Hour},
+	{"min", Minute},
+	{"s", Second},
+	{"ms", Millisecond},
+	{"us", Microsecond},
+	{"ns", Nanosecond},
+}
+
+// absDuration returns the absolute value of d, which unlike -d does not overflow for
+// math.MinInt64.
+func absDuration(d time.Duration) uint64 {
+	if d < 0 {
+		return uint64(-(d + 1)) + 1
+	}
+	return uint64(d)
+}
+
+// FormatTimespan returns d as a time span string in systemd syntax, e.g.
+// "1y 2M 3d 5h 10min 15s". The duration is split greatest unit first and zero
+// components are omitted. Years and months have the same fixed lengths as in
+// ParseTimespan (365.25 and 30.4375 days), so the result parses back to d. A zero
+// duration is "0", Infinity is "infinity", and a negative duration is prefixed with
+// "-".
+func FormatTimespan(d time.Duration) string {
+	return FormatTimespanMaxUnit(d, Year)
+}
+
+// FormatTimespanMaxUnit returns d like FormatTimespan, but without units greater than
+// maxUnit, e.g. 3 days with Hour is "72h" rather than "3d". The result still parses
+// back to d.
+func FormatTimespanMaxUnit(d, maxUnit time.Duration) string {
+	switch d {
+	case 0:
+		return "0"
+	case Infinity:
+		return "infinity"
+	}
+
+	var b strings.Builder
+	u := absDuration(d)
+	if d < 0 {
+		b.WriteByte('-')
+	}
+	sep := ""
+	for _, tu := range timespanUnits {
+		if tu.unit > maxUnit && tu.unit != Nanosecond {
+			continue
+		}
+		n := u / uint64(tu.unit)
+		if n == 0 {
+			continue
+		}
+		u -= n * uint64(tu.unit)
+		b.WriteString(sep)
+		b.WriteString(strconv.FormatUint(n, 10))
+		b.WriteString(tu.name)
+		sep = " "
+	}
+
+	return b.String()
+}
+
+// FormatTimespanFlat returns d as a time span string in a single unit with a decimal
+// fraction, e.g. 90 minutes is "90min" with Minute and "1.5h" with Hour, rather than
+// the "1h 30min" of FormatTimespan. unit is rounded down to one of the units used by
+// FormatTimespan, e.g. 90 * Minute is Hour. The fraction has at most 9 digits, the
+// precision of ParseTimespan, and is truncated beyond that. Zero, Infinity, and
+// negative durations are formatted as by FormatTimespan.
+func FormatTimespanFlat(d, unit time.Duration) string {
+	switch d {
+	case 0:
+		return "0"
+	case Infinity:
+		return "infinity"
+	}
+
+	tu := timespanUnits[len(timespanUnits)-1]
+	for _, u := range timespanUnits {
+		if u.unit <= unit {
+			tu = u
+			break
+		}
+	}
+
+	var b strings.Builder
+	u := absDuration(d)
+	if d < 0 {
+		b.WriteByte('-')
+	}
+	b.WriteString(strconv.FormatUint(u/uint64(tu.unit), 10))
+
+	// long division, as the remainder times 10^9 may overflow
+	rem := u % uint64(tu.unit)
+	if rem > 0 {
+		b.WriteByte('.')
+		for digits := 0; digits < 9 && rem > 0; digits++ {
+			rem *= 10
+			b.WriteByte(byte('0' + rem/uint64(tu.unit)))
+			rem %= uint64(tu.unit)
+		}
+	}
+	b.WriteString(tu.name)
+
+	return b.String()
+}
+
+// FormatTimestamp returns t as a timestamp string in systemd syntax, e.g.
+// "2009-11-10 18:15:22 UTC" or "2009-11-10 18:15:22.5 +01:00". The timezone is "UTC"
+// for UTC and an offset otherwise. Fractional seconds are only included if present,
+// with trailing zeros removed. The offset includes seconds if it has any, e.g.
+// "+00:19:32" for Europe/Amsterdam in 1920. The result parses back to the same instant
+// with ParseTimestamp for years 0-9999.
+func FormatTimestamp(t time.Time) string {
+	if t.Location() == time.UTC {
+		return t.Format("2006-01-02 15:04:05.999999999 UTC")
+	}
+	_, offset := t.Zone()
+	if offset%60 == 0 {
+		return t.Format("2006-01-02 15:04:05.999999999 -07:00")
+	}
+
+	// the "-07:00:00" layout gets the sign wrong for offsets under a minute
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("%s %c%02d:%02d:%02d", t.Format("2006-01-02 15:04:05.999999999"),
+		sign, offset/3600, offset/60%60, offset%60)
+}
+
+// FormatTimestampWithWeekday returns t like FormatTimestamp, but prefixed with the
+// abbreviated weekday, e.g. "Tue 2009-11-10 18:15:22 UTC".
+func FormatTimestampWithWeekday(t time.Time) string {
+	return t.Format("Mon ") + FormatTimestamp(t)
+}
+
+// FormatUnix returns t as a unix timestamp string, e.g. "@1395716396" or
+// "@1395716396.654321". Fractional seconds are only included if present, with
+// trailing zeros removed. Times before the UNIX epoch are prefixed with "-", which
+// ParseTimestamp does not accept; other results parse back to the same instant.
+func FormatUnix(t time.Time) string {
+	sec, nsec := t.Unix(), int64(t.Nanosecond())
+	neg := sec < 0
+	if neg {
+		sec = -sec
+		if nsec > 0 {
+			sec--
+			nsec = int64(Second) - nsec
+		}
+	}
+
+	var b strings.Builder
+	b.WriteByte('@')
+	if neg {
+		b.WriteByte('-')
+	}
+	b.WriteString(strconv.FormatInt(sec, 10))
+	if nsec > 0 {
+		frac := strconv.FormatInt(nsec+int64(Second), 10)[1:] // zero-padded to 9 digits
+		b.WriteByte('.')
+		b.WriteString(strings.TrimRight(frac, "0"))
+	}
+	return b.String()
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	systemdtime "gitlab.com/allddd/go-systemd-time"
+)
+
+func TestFormatTimespan(t *testing.T) {
+	cases := []struct {
+		input  time.Duration
+		expect string
+	}{
+		{0, "0"},
+		{systemdtime.Nanosecond, "1ns"},
+		{1500 * systemdtime.Millisecond, "1s 500ms"},
+		{90 * systemdtime.Minute, "1h 30min"},
+		{systemdtime.Week + 2*systemdtime.Day, "1w 2d"},
+		{systemdtime.Month, "1M"},
+		{systemdtime.Year, "1y"},
+		{2 * systemdtime.Year, "2y"},
+		{12 * systemdtime.Month, "1y"},
+		{systemdtime.Year - systemdtime.Nanosecond, "11M 4w 2d 10h 29min 59s 999ms 999us 999ns"},
+		{systemdtime.Year + 12*systemdtime.Month + 2*systemdtime.Week + 3*systemdtime.Day + 5*systemdtime.Hour + 10*systemdtime.Minute + 15*systemdtime.Second, "2y 2w 3d 5h 10min 15s"},
+		{1001001 * systemdtime.Nanosecond, "1ms 1us 1ns"},
+		{-90 * systemdtime.Minute, "-1h 30min"},
+		{-systemdtime.Nanosecond, "-1ns"},
+		{systemdtime.Infinity, "infinity"},
+		{systemdtime.Infinity - 1, "292y 3M 1w 16h 17min 16s 854ms 775us 806ns"},
+	}
+	for _, tc := range cases {
+		got := systemdtime.FormatTimespan(tc.input)
+		if got != tc.expect {
+			t.Errorf("%v: expected %q, got %q", tc.input, tc.expect, got)
+		}
+	}
+
+	// round trip
+	for _, d := range []time.Duration{
+		0,
+		systemdtime.Nanosecond,
+		systemdtime.Month,
+		systemdtime.Month - systemdtime.Nanosecond,
+		systemdtime.Year + systemdtime.Month,
+		123456789 * systemdtime.Microsecond,
+		systemdtime.Infinity,
+		systemdtime.Infinity - 1,
+	} {
+		got, err := systemdtime.ParseTimespan(systemdtime.FormatTimespan(d))
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", d, err)
+			continue
+		}
+		if got != d {
+			t.Errorf("%v: expected %v after round trip, got %v", d, d, got)
+		}
+	}
+
+	if got := systemdtime.FormatTimespan(math.MinInt64); got[0] != '-' {
+		t.Errorf("%v: expected negative time span, got %q", time.Duration(math.MinInt64), got)
+	}
+}
+
+func TestFormatTimespanMaxUnit(t *testing.T) {
+	cases := []struct {
+		input  time.Duration
+		max    time.Duration
+		expect string
+	}{
+		{3 * systemdtime.Day, systemdtime.Hour, "72h"},
+		{3*systemdtime.Day + 90*systemdtime.Minute, systemdtime.Hour, "73h 30min"},
+		{2*systemdtime.Week + 30*systemdtime.Second, systemdtime.Hour, "336h 30s"},
+		{3 * systemdtime.Day, systemdtime.Minute, "4320min"},
+		{90*systemdtime.Second + 500*systemdtime.Millisecond, systemdtime.Minute, "1min 30s 500ms"},
+		{90 * systemdtime.Second, systemdtime.Second, "90s"},
+		{3 * systemdtime.Day, 90 * systemdtime.Minute, "72h"},
+		{systemdtime.Year, systemdtime.Day, "365d 6h"},
+		{systemdtime.Microsecond, systemdtime.Nanosecond, "1000ns"},
+		{systemdtime.Microsecond, 0, "1000ns"},
+		{-3 * systemdtime.Day, systemdtime.Hour, "-72h"},
+		{0, systemdtime.Hour, "0"},
+	}
+	for _, tc := range cases {
+		got := systemdtime.FormatTimespanMaxUnit(tc.input, tc.max)
+		if got != tc.expect {
+			t.Errorf("%v (max %v): expected %q, got %q", tc.input, tc.max, tc.expect, got)
+		}
+		if tc.input <= 0 {
+			continue
+		}
+		if d, err := systemdtime.ParseTimespan(got); err != nil || d != tc.input {
+			t.Errorf("%q: expected %v after round trip, got %v (%v)", got, tc.input, d, err)
+		}
+	}
+}
+
+func TestFormatTimespanFlat(t *testing.T) {
+	cases := []struct {
+		input     time.Duration
+		unit      time.Duration
+		expect    string
+		roundTrip bool
+	}{
+		{90 * systemdtime.Minute, systemdtime.Minute, "90min", true},
+		{90 * systemdtime.Minute, systemdtime.Hour, "1.5h", true},
+		{90 * systemdtime.Second, systemdtime.Minute, "1.5min", true},
+		{3 * systemdtime.Day, systemdtime.Hour, "72h", true},
+		{36 * systemdtime.Hour, systemdtime.Day, "1.5d", true},
+		{systemdtime.Year, systemdtime.Day, "365.25d", true},
+		{1500 * systemdtime.Microsecond, systemdtime.Millisecond, "1.5ms", true},
+		{systemdtime.Minute + systemdtime.Nanosecond, systemdtime.Second, "60.000000001s", true},
+		{100 * systemdtime.Minute, systemdtime.Hour, "1.666666666h", false},
+		{systemdtime.Year, systemdtime.Year, "1y", true},
+		{90 * systemdtime.Minute, 90 * systemdtime.Minute, "1.5h", true},
+		{90 * systemdtime.Minute, 0, "5400000000000ns", true},
+		{-90 * systemdtime.Minute, systemdtime.Hour, "-1.5h", true},
+		{0, systemdtime.Hour, "0", true},
+		{systemdtime.Infinity, systemdtime.Hour, "infinity", true},
+	}
+	for _, tc := range cases {
+		got := systemdtime.FormatTimespanFlat(tc.input, tc.unit)
+		if got != tc.expect {
+			t.Errorf("%v (unit %v): expected %q, got %q", tc.input, tc.unit, tc.expect, got)
+		}
+		if !tc.roundTrip {
+			continue
+		}
+		if d, err := systemdtime.ParseTimespan(got); err != nil || d != tc.input {
+			t.Errorf("%q: expected %v after round trip, got %v (%v)", got, tc.input, d, err)
+		}
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	cases := []struct {
+		input  time.Time
+		expect string
+	}{
+		{time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), "2009-11-10 18:15:22 UTC"},
+		{time.Date(2009, 11, 10, 18, 15, 22, 500000000, time.UTC), "2009-11-10 18:15:22.5 UTC"},
+		{time.Date(2009, 11, 10, 18, 15, 22, 654321000, time.UTC), "2009-11-10 18:15:22.654321 UTC"},
+		{time.Date(2009, 11, 10, 18, 15, 22, 1, time.UTC), "2009-11-10 18:15:22.000000001 UTC"},
+		{time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", 3600)), "2009-11-10 18:15:22 +01:00"},
+		{time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", -5*3600-30*60)), "2009-11-10 18:15:22 -05:30"},
+		{time.Date(2009, 11, 10, 18, 15, 22, 0, tzTokyo), "2009-11-10 18:15:22 +09:00"},
+		{time.Date(2009, 11, 10, 18, 15, 22, 0, tzLondon), "2009-11-10 18:15:22 +00:00"},
+		{time.Date(1920, 1, 1, 0, 0, 0, 0, tzAmsterdam), "1920-01-01 00:00:00 +00:19:32"},
+		{time.Date(1920, 1, 1, 0, 0, 0, 0, time.FixedZone("", -30)), "1920-01-01 00:00:00 -00:00:30"},
+	}
+	for _, tc := range cases {
+		got := systemdtime.FormatTimestamp(tc.input)
+		if got != tc.expect {
+			t.Errorf("%v: expected %q, got %q", tc.input, tc.expect, got)
+		}
+
+		// round trip
+		parsed, err := systemdtime.ParseTimestamp(got)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", got, err)
+			continue
+		}
+		if !parsed.Equal(tc.input) {
+			t.Errorf("%q: expected %v after round trip, got %v", got, tc.input, parsed)
+		}
+	}
+
+	// weekday
+	tue := time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC)
+	got := systemdtime.FormatTimestampWithWeekday(tue)
+	if expect := "Tue 2009-11-10 18:15:22 UTC"; got != expect {
+		t.Errorf("%v: expected %q, got %q", tue, expect, got)
+	}
+	if parsed, err := systemdtime.ParseTimestamp(got); err != nil || !parsed.Equal(tue) {
+		t.Errorf("%q: expected %v after round trip, got %v (%v)", got, tue, parsed, err)
+	}
+}
+
+func TestFormatUnix(t *testing.T) {
+	cases := []struct {
+		input  time.Time
+		expect string
+	}{
+		{time.Unix(0, 0), "@0"},
+		{time.Unix(1395716396, 0), "@1395716396"},
+		{time.Unix(1395716396, 654321000), "@1395716396.654321"},
+		{time.Unix(1395716396, 500000000), "@1395716396.5"},
+		{time.Unix(1395716396, 1), "@1395716396.000000001"},
+		{time.Unix(1395716396, 654321000).In(time.FixedZone("", 3600)), "@1395716396.654321"},
+		{time.Unix(-1, 0), "@-1"},
+		{time.Unix(-2, 500000000), "@-1.5"},
+		{time.Unix(0, -500000000), "@-0.5"},
+	}
+	for _, tc := range cases {
+		got := systemdtime.FormatUnix(tc.input)
+		if got != tc.expect {
+			t.Errorf("%v: expected %q, got %q", tc.input, tc.expect, got)
+		}
+
+		// round trip
+		if tc.input.Before(time.Unix(0, 0)) {
+			continue
+		}
+		parsed, err := systemdtime.ParseTimestamp(got)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", got, err)
+			continue
+		}
+		if !parsed.Equal(tc.input) {
+			t.Errorf("%q: expected %v after round trip, got %v", got, tc.input, parsed)
+		}
+	}
+}
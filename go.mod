@@ -1,3 +1,3 @@
 module gitlab.com/allddd/go-systemd-time
 
-go 1.1
+go 1.19
@@ -0,0 +1,303 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseISO8601Duration parses an ISO 8601 duration string and returns the duration.
+//
+// Durations start with "P", followed by date components (years "Y", months "M",
+// weeks "W", days "D") and, after "T", time components (hours "H", minutes "M",
+// seconds "S"). Components must be given in this order, each at most once, and at
+// least one must be present. Years and months use the same fixed lengths as
+// ParseTimespan (365.25 and 30.4375 days). Any component can have a fraction.
+//
+// Examples for valid durations:
+//
+//	P1D
+//	P2W
+//	PT1H30M
+//	PT0.5S
+//	P1Y2M10DT2H30M
+func ParseISO8601Duration(s string) (time.Duration, error) {
+	if isBlank(s) {
+		return 0, parseErrorf(s, 0, "expected ISO 8601 duration, got %q: %w", s, ErrEmptyInput)
+	}
+	if s[0] != 'P' {
+		return 0, parseErrorf(s, 0, "expected 'P' at start of ISO 8601 duration, got %q", s)
+	}
+
+	designators := "YMWDTHMS"
+	units := [...]time.Duration{Year, Month, Week, Day, 0, Hour, Minute, Second}
+	next := 0 // index of the next allowed designator
+	timePart := false
+	terms := 0
+
+	var d time.Duration
+	for i := 1; i < len(s); {
+		if s[i] == 'T' {
+			if timePart {
+				return 0, parseErrorf(s, i, "expected 'T' once, got %q", s)
+			}
+			timePart = true
+			next = strings.IndexByte(designators, 'T') + 1
+			i++
+			continue
+		}
+
+		// read number
+		num, j, err := readNum(s, i)
+		if err != nil {
+			return 0, err
+		}
+		nsec := 0
+		if j < len(s) && s[j] == '.' {
+			nsec, j, err = readFrac(s, j+1)
+			if err != nil {
+				return 0, err
+			}
+		}
+		if j >= len(s) {
+			return 0, parseErrorf(s, j, "expected designator after %q in %q", s[i:j], s)
+		}
+
+		// read designator, 'M' is months before 'T' and minutes after
+		k := next
+		for k < len(designators) && (designators[k] != s[j] || designators[k] == 'T' || (k > 4) != timePart) {
+			k++
+		}
+		if k == len(designators) {
+			return 0, parseErrorf(s, j, "expected designator, got %q in %q", string(s[j]), s)
+		}
+		unit := units[k]
+		if time.Duration(num) > math.MaxInt64/unit {
+			return 0, parseErrorf(s, i, "expected duration within range, got %q in %q", s[i:j+1], s)
+		}
+		term := time.Duration(num) * unit
+		if nsec > 0 {
+			// split into whole seconds and remainder to avoid overflow
+			frac := time.Duration(nsec)*(unit/Second) + time.Duration(nsec)*(unit%Second)/Second
+			if term > math.MaxInt64-frac {
+				return 0, parseErrorf(s, i, "expected duration within range, got %q in %q", s[i:j+1], s)
+			}
+			term += frac
+		}
+		if d > math.MaxInt64-term {
+			return 0, parseErrorf(s, i, "expected duration within range, got %q", s)
+		}
+		d += term
+		next = k + 1
+		terms++
+		i = j + 1
+	}
+
+	if terms == 0 || s[len(s)-1] == 'T' {
+		return 0, parseErrorf(s, len(s), "expected component in ISO 8601 duration, got %q", s)
+	}
+
+	return d, nil
+}
+
+// FormatISO8601Duration returns d as an ISO 8601 duration string, e.g.
+// "P1Y2M10DT2H30M". Years and months have the same fixed lengths as in
+// ParseISO8601Duration, so the result parses back to d. A whole number of weeks is
+// written as weeks only (e.g. "P2W"), fractional seconds are only included if present
+// (e.g. "PT0.5S"), and a zero duration is "PT0S". A negative duration is prefixed with
+// "-", which ISO 8601 and ParseISO8601Duration do not support.
+func FormatISO8601Duration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	u := absDuration(d)
+	if d < 0 {
+		b.WriteByte('-')
+	}
+	b.WriteByte('P')
+
+	if u%uint64(Week) == 0 {
+		b.WriteString(strconv.FormatUint(u/uint64(Week), 10))
+		b.WriteByte('W')
+		return b.String()
+	}
+
+	write := func(unit time.Duration, designator byte) {
+		if n := u / uint64(unit); n > 0 {
+			b.WriteString(strconv.FormatUint(n, 10))
+			b.WriteByte(designator)
+			u -= n * uint64(unit)
+		}
+	}
+	write(Year, 'Y')
+	write(Month, 'M')
+	write(Day, 'D')
+	if u == 0 {
+		return b.String()
+	}
+
+	b.WriteByte('T')
+	write(Hour, 'H')
+	write(Minute, 'M')
+	if u > 0 {
+		b.WriteString(strconv.FormatUint(u/uint64(Second), 10))
+		if nsec := u % uint64(Second); nsec > 0 {
+			frac := strconv.FormatUint(nsec+uint64(Second), 10)[1:] // zero-padded to 9 digits
+			b.WriteByte('.')
+			b.WriteString(strings.TrimRight(frac, "0"))
+		}
+		b.WriteByte('S')
+	}
+
+	return b.String()
+}
+
+// ParseInterval8601 parses an ISO 8601 time interval string and returns its start and
+// end. The interval is given as "<start>/<end>", "<start>/<duration>", or
+// "<duration>/<end>", where start and end are timestamps as accepted by ParseTimestamp
+// and duration is an ISO 8601 duration as accepted by ParseISO8601Duration. The end
+// must not be before the start.
+//
+// Examples for valid intervals:
+//
+//	2009-11-10T00:00:00Z/2009-11-11T00:00:00Z
+//	2009-11-10T00:00:00Z/P1D
+//	P1D/2009-11-11T00:00:00Z
+func ParseInterval8601(s string) (time.Time, time.Time, error) {
+	slash := strings.IndexByte(s, '/')
+	if slash < 0 {
+		return time.Time{}, time.Time{}, parseErrorf(s, len(s), "expected '/' in ISO 8601 interval, got %q", s)
+	}
+	first, second := s[:slash], s[slash+1:]
+
+	var start, end time.Time
+	var err error
+	switch {
+	case strings.HasPrefix(first, "P") && strings.HasPrefix(second, "P"):
+		return time.Time{}, time.Time{}, parseErrorf(s, slash+1, "expected at most one duration in ISO 8601 interval, got %q", s)
+	case strings.HasPrefix(first, "P"):
+		end, err = ParseTimestamp(second)
+		if err != nil {
+			return time.Time{}, time.Time{}, shiftParseError(err, s, slash+1)
+		}
+		d, err := ParseISO8601Duration(first)
+		if err != nil {
+			return time.Time{}, time.Time{}, shiftParseError(err, s, 0)
+		}
+		start = end.Add(-d)
+	case strings.HasPrefix(second, "P"):
+		start, err = ParseTimestamp(first)
+		if err != nil {
+			return time.Time{}, time.Time{}, shiftParseError(err, s, 0)
+		}
+		d, err := ParseISO8601Duration(second)
+		if err != nil {
+			return time.Time{}, time.Time{}, shiftParseError(err, s, slash+1)
+		}
+		end = start.Add(d)
+	default:
+		start, err = ParseTimestamp(first)
+		if err != nil {
+			return time.Time{}, time.Time{}, shiftParseError(err, s, 0)
+		}
+		end, err = ParseTimestamp(second)
+		if err != nil {
+			return time.Time{}, time.Time{}, shiftParseError(err, s, slash+1)
+		}
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("expected end not before start, got %s before %s in %q", end, start, s)
+	}
+
+	return start, end, nil
+}
+
+// ParseWeekInterval8601 parses an ISO 8601 interval of weeks ("YYYY-Www/YYYY-Www") and
+// returns 00:00:00 UTC of the Monday of the first week and of the Sunday of the last
+// week. Weeks are ISO weeks, so the first week may start in the previous calendar year
+// and the last week may end in the next. The last week must not be before the first.
+//
+// Examples for valid week intervals:
+//
+//	2009-W46/2009-W48
+//	2009-W53/2010-W01
+//	2009-W46/2009-W46
+func ParseWeekInterval8601(s string) (time.Time, time.Time, error) {
+	if isBlank(s) {
+		return time.Time{}, time.Time{}, parseErrorf(s, 0, "expected week interval, got %q: %w", s, ErrEmptyInput)
+	}
+	slash := strings.IndexByte(s, '/')
+	if slash < 0 {
+		return time.Time{}, time.Time{}, parseErrorf(s, len(s), "expected '/' in week interval, got %q", s)
+	}
+
+	startYear, startWeek, err := handleISOWeek(s[:slash])
+	if err != nil {
+		return time.Time{}, time.Time{}, shiftParseError(err, s, 0)
+	}
+	endYear, endWeek, err := handleISOWeek(s[slash+1:])
+	if err != nil {
+		return time.Time{}, time.Time{}, shiftParseError(err, s, slash+1)
+	}
+
+	start := isoWeekDate(startYear, startWeek, 1)
+	end := isoWeekDate(endYear, endWeek, 7)
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("expected last week not before first week, got %q", s)
+	}
+
+	return start, end, nil
+}
+
+// handleISOWeek parses an ISO week ("YYYY-Www") that makes up all of s and returns
+// the ISO year, week, and any error.
+func handleISOWeek(s string) (int, int, error) {
+	year, i, err := readNum(s, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	if i != 4 { // 4 is the required digit count for YYYY
+		return 0, 0, parseErrorf(s, 0, "expected 4-digit year, got %d digits in %q", i, s)
+	}
+	if !strings.HasPrefix(s[i:], "-W") {
+		return 0, 0, parseErrorf(s, i, "expected week (YYYY-Www), got %q", s)
+	}
+
+	week, i, err := handleWeek(s, i+2, year)
+	if err != nil {
+		return 0, 0, err
+	}
+	if i < len(s) {
+		return 0, 0, parseErrorf(s, i, "expected end of week, got %q in %q", s[i:], s)
+	}
+
+	return year, week, nil
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime_test
+
+import (
+	"testing"
+	"time"
+
+	systemdtime "gitlab.com/allddd/go-systemd-time"
+)
+
+func TestParseISO8601Duration(t *testing.T) {
+	cases := []struct {
+		input     string
+		expect    time.Duration
+		expectErr bool
+	}{
+		{"P1D", systemdtime.Day, false},
+		{"P2W", 2 * systemdtime.Week, false},
+		{"P1Y", systemdtime.Year, false},
+		{"P1M", systemdtime.Month, false},
+		{"PT1M", systemdtime.Minute, false},
+		{"PT1H30M", 90 * systemdtime.Minute, false},
+		{"PT0.5S", 500 * systemdtime.Millisecond, false},
+		{"P0.5D", 12 * systemdtime.Hour, false},
+		{"P1DT12H", 36 * systemdtime.Hour, false},
+		{"P1Y2M10DT2H30M", systemdtime.Year + 2*systemdtime.Month + 10*systemdtime.Day + 2*systemdtime.Hour + 30*systemdtime.Minute, false},
+		{"PT0S", 0, false},
+		{"", 0, true},
+		{"P", 0, true},
+		{"PT", 0, true},
+		{"P292Y11M", 0, true},
+		{"PT2562047H59M", 0, true},
+		{"PT2562047H47M16.9S", 0, true},
+		{"P292.3Y", 0, true},
+		{"P1DT", 0, true},
+		{"1D", 0, true},
+		{"P1", 0, true},
+		{"P1H", 0, true},
+		{"PT1D", 0, true},
+		{"P1D1Y", 0, true},
+		{"P1D1D", 0, true},
+		{"PT1HT1M", 0, true},
+		{"P1X", 0, true},
+		{"PD", 0, true},
+		{"P999999999999Y", 0, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseISO8601Duration(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expect {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+}
+
+func TestFormatISO8601Duration(t *testing.T) {
+	cases := []struct {
+		input  time.Duration
+		expect string
+	}{
+		{0, "PT0S"},
+		{systemdtime.Year + 2*systemdtime.Month + 10*systemdtime.Day + 2*systemdtime.Hour + 30*systemdtime.Minute, "P1Y2M10DT2H30M"},
+		{2 * systemdtime.Week, "P2W"},
+		{systemdtime.Week + systemdtime.Day, "P8D"},
+		{systemdtime.Day, "P1D"},
+		{90 * systemdtime.Minute, "PT1H30M"},
+		{500 * systemdtime.Millisecond, "PT0.5S"},
+		{systemdtime.Nanosecond, "PT0.000000001S"},
+		{systemdtime.Day + 90*systemdtime.Second, "P1DT1M30S"},
+		{systemdtime.Year, "P1Y"},
+		{-2 * systemdtime.Hour, "-PT2H"},
+	}
+	for _, tc := range cases {
+		got := systemdtime.FormatISO8601Duration(tc.input)
+		if got != tc.expect {
+			t.Errorf("%v: expected %q, got %q", tc.input, tc.expect, got)
+		}
+		if tc.input < 0 {
+			continue
+		}
+		if d, err := systemdtime.ParseISO8601Duration(got); err != nil || d != tc.input {
+			t.Errorf("%q: expected %v after round trip, got %v (%v)", got, tc.input, d, err)
+		}
+	}
+}
+
+func TestParseInterval8601(t *testing.T) {
+	cases := []struct {
+		input       string
+		expectStart time.Time
+		expectEnd   time.Time
+		expectErr   bool
+	}{
+		{"2009-11-10T00:00:00Z/2009-11-11T00:00:00Z", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"2009-11-10T00:00:00Z/P1D", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"P1D/2009-11-11T00:00:00Z", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"2009-11-10T18:15:22+01:00/PT1H30M", time.Date(2009, 11, 10, 17, 15, 22, 0, time.UTC), time.Date(2009, 11, 10, 18, 45, 22, 0, time.UTC), false},
+		{"2009-11-10T00:00:00Z/2009-11-10T00:00:00Z", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"2009-11-11T00:00:00Z/2009-11-10T00:00:00Z", time.Time{}, time.Time{}, true},
+		{"P1D/P2D", time.Time{}, time.Time{}, true},
+		{"2009-11-10T00:00:00Z", time.Time{}, time.Time{}, true},
+		{"2009-11-10T00:00:00Z/P", time.Time{}, time.Time{}, true},
+		{"2009-11-10T00:00:00Z/", time.Time{}, time.Time{}, true},
+		{"/2009-11-10T00:00:00Z", time.Time{}, time.Time{}, true},
+		{"P1X/2009-11-10T00:00:00Z", time.Time{}, time.Time{}, true},
+	}
+	for _, tc := range cases {
+		start, end, err := systemdtime.ParseInterval8601(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !start.Equal(tc.expectStart) || !end.Equal(tc.expectEnd) {
+			t.Errorf("%q: expected %v/%v, got %v/%v", tc.input, tc.expectStart, tc.expectEnd, start, end)
+		}
+	}
+}
+
+func TestParseWeekInterval8601(t *testing.T) {
+	cases := []struct {
+		input       string
+		expectStart time.Time
+		expectEnd   time.Time
+		expectErr   bool
+	}{
+		{"2009-W46/2009-W48", time.Date(2009, 11, 9, 0, 0, 0, 0, time.UTC), time.Date(2009, 11, 29, 0, 0, 0, 0, time.UTC), false},
+		{"2009-W46/2009-W46", time.Date(2009, 11, 9, 0, 0, 0, 0, time.UTC), time.Date(2009, 11, 15, 0, 0, 0, 0, time.UTC), false},
+		{"2009-W01/2009-W01", time.Date(2008, 12, 29, 0, 0, 0, 0, time.UTC), time.Date(2009, 1, 4, 0, 0, 0, 0, time.UTC), false},
+		{"2009-W53/2010-W01", time.Date(2009, 12, 28, 0, 0, 0, 0, time.UTC), time.Date(2010, 1, 10, 0, 0, 0, 0, time.UTC), false},
+		{"2009-W48/2009-W46", time.Time{}, time.Time{}, true},
+		{"2010-W53/2011-W01", time.Time{}, time.Time{}, true},
+		{"2009-W00/2009-W01", time.Time{}, time.Time{}, true},
+		{"2009-W46", time.Time{}, time.Time{}, true},
+		{"2009-W46/", time.Time{}, time.Time{}, true},
+		{"2009-W46/2009-W48-7", time.Time{}, time.Time{}, true},
+		{"09-W46/09-W48", time.Time{}, time.Time{}, true},
+		{"2009-46/2009-48", time.Time{}, time.Time{}, true},
+		{"2009-W4/2009-W48", time.Time{}, time.Time{}, true},
+		{"", time.Time{}, time.Time{}, true},
+	}
+	for _, tc := range cases {
+		start, end, err := systemdtime.ParseWeekInterval8601(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !start.Equal(tc.expectStart) || !end.Equal(tc.expectEnd) {
+			t.Errorf("%q: expected %v/%v, got %v/%v", tc.input, tc.expectStart, tc.expectEnd, start, end)
+		}
+		if start.Weekday() != time.Monday || end.Weekday() != time.Sunday {
+			t.Errorf("%q: expected Monday/Sunday, got %s/%s", tc.input, start.Weekday(), end.Weekday())
+		}
+	}
+}
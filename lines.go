@@ -0,0 +1,98 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ParseTimespanLines parses each line read from r as a time span (see ParseTimespan)
+// and returns the durations and errors, which have the same length and order. The
+// error for a valid line is nil; otherwise it includes the line number. If skipBlank
+// is set, blank lines are skipped instead of reported as empty input. An error reading
+// from r is returned as the last entry.
+func ParseTimespanLines(r io.Reader, skipBlank bool) ([]time.Duration, []error) {
+	var durations []time.Duration
+	var errs []error
+	err := scanLines(r, skipBlank, func(n int, line string) {
+		d, err := ParseTimespan(line)
+		if err != nil {
+			err = fmt.Errorf("line %d: %w", n, err)
+		}
+		durations = append(durations, d)
+		errs = append(errs, err)
+	})
+	if err != nil {
+		durations = append(durations, 0)
+		errs = append(errs, err)
+	}
+	return durations, errs
+}
+
+// ParseTimestampLines parses each line read from r as a timestamp (see ParseTimestamp)
+// and returns the times and errors like ParseTimespanLines. The optional now parameter
+// specifies the reference time for relative timestamps on all lines. If not provided,
+// the current time when ParseTimestampLines is called is used.
+func ParseTimestampLines(r io.Reader, skipBlank bool, now ...time.Time) ([]time.Time, []error) {
+	ref := time.Now()
+	if len(now) > 0 {
+		ref = now[0]
+	}
+
+	var times []time.Time
+	var errs []error
+	err := scanLines(r, skipBlank, func(n int, line string) {
+		t, err := ParseTimestamp(line, ref)
+		if err != nil {
+			err = fmt.Errorf("line %d: %w", n, err)
+		}
+		times = append(times, t)
+		errs = append(errs, err)
+	})
+	if err != nil {
+		times = append(times, time.Time{})
+		errs = append(errs, err)
+	}
+	return times, errs
+}
+
+// scanLines calls fn with the 1-based line number and content of each line read from
+// r, skipping blank lines if skipBlank is set, and returns any read error.
+func scanLines(r io.Reader, skipBlank bool, fn func(n int, line string)) error {
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if skipBlank && isBlank(line) {
+			continue
+		}
+		fn(n, line)
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("reading lines: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	systemdtime "gitlab.com/allddd/go-systemd-time"
+)
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestParseTimespanLines(t *testing.T) {
+	input := "1h 30min\n5 parsecs\n\n  \r\n100ms\r\n"
+	cases := []struct {
+		skipBlank bool
+		expect    []time.Duration
+		expectErr []string // substring of the error, "" for none
+	}{
+		{true, []time.Duration{90 * systemdtime.Minute, 0, 100 * systemdtime.Millisecond}, []string{"", "line 2", ""}},
+		{false, []time.Duration{90 * systemdtime.Minute, 0, 0, 0, 100 * systemdtime.Millisecond}, []string{"", "line 2", "line 3", "line 4", ""}},
+	}
+	for _, tc := range cases {
+		got, errs := systemdtime.ParseTimespanLines(strings.NewReader(input), tc.skipBlank)
+		if len(got) != len(tc.expect) || len(errs) != len(tc.expectErr) {
+			t.Errorf("skipBlank %t: expected %d results, got %d durations and %d errors", tc.skipBlank, len(tc.expect), len(got), len(errs))
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.expect[i] {
+				t.Errorf("skipBlank %t, entry %d: expected %v, got %v", tc.skipBlank, i, tc.expect[i], got[i])
+			}
+			if tc.expectErr[i] == "" && errs[i] != nil {
+				t.Errorf("skipBlank %t, entry %d: unexpected error: %v", tc.skipBlank, i, errs[i])
+			} else if tc.expectErr[i] != "" && (errs[i] == nil || !strings.Contains(errs[i].Error(), tc.expectErr[i])) {
+				t.Errorf("skipBlank %t, entry %d: expected error containing %q, got %v", tc.skipBlank, i, tc.expectErr[i], errs[i])
+			}
+		}
+	}
+
+	// errors wrap the parse error
+	_, errs := systemdtime.ParseTimespanLines(strings.NewReader("\n"), false)
+	if len(errs) != 1 || !errors.Is(errs[0], systemdtime.ErrEmptyInput) {
+		t.Errorf("expected ErrEmptyInput, got %v", errs)
+	}
+
+	// read errors are reported last
+	got, errs := systemdtime.ParseTimespanLines(errReader{}, true)
+	if len(got) != 1 || len(errs) != 1 || errs[0] == nil {
+		t.Errorf("expected read error, got %v, %v", got, errs)
+	}
+}
+
+func TestParseTimestampLines(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	input := "2009-11-10 18:15:22\n\nWed 2009-11-10\n+1h\n"
+	got, errs := systemdtime.ParseTimestampLines(strings.NewReader(input), true, now)
+	expect := []time.Time{time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), {}, now.Add(systemdtime.Hour)}
+	if len(got) != len(expect) || len(errs) != len(expect) {
+		t.Fatalf("expected %d results, got %d times and %d errors", len(expect), len(got), len(errs))
+	}
+	for i := range got {
+		if !got[i].Equal(expect[i]) {
+			t.Errorf("entry %d: expected %v, got %v", i, expect[i], got[i])
+		}
+	}
+	if errs[0] != nil || errs[2] != nil {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if errs[1] == nil || !strings.Contains(errs[1].Error(), "line 3") {
+		t.Errorf("expected error for line 3, got %v", errs[1])
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime
+
+import (
+	"sync"
+	"time"
+)
+
+// locationCache caches locations loaded from the timezone database by name.
+type locationCache struct {
+	mu        sync.Mutex
+	locations map[string]*time.Location
+}
+
+// load returns the location with the given name like time.LoadLocation, loading it
+// only once. Failed lookups are not cached.
+func (c *locationCache) load(name string) (*time.Location, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if loc, ok := c.locations[name]; ok {
+		return loc, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	if c.locations == nil {
+		c.locations = make(map[string]*time.Location)
+	}
+	c.locations[name] = loc
+	return loc, nil
+}
+
+// Parser parses timestamps like ParseTimestamp, but caches the timezones it loads from
+// the IANA timezone database (e.g. "America/New_York"), so repeated timezone names are
+// only looked up once. A Parser is safe for concurrent use and must not be copied
+// after first use.
+type Parser struct {
+	locations locationCache
+}
+
+// NewParser returns a new Parser with an empty timezone cache.
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// ParseTimestamp parses a timestamp string like ParseTimestamp.
+func (p *Parser) ParseTimestamp(s string, now ...time.Time) (time.Time, error) {
+	return p.ParseTimestampWith(s, ParseTimestampOptions{}, now...)
+}
+
+// ParseTimestampWith parses a timestamp string like ParseTimestampWith.
+func (p *Parser) ParseTimestampWith(s string, opts ParseTimestampOptions, now ...time.Time) (time.Time, error) {
+	opts.locations = &p.locations
+	return ParseTimestampWith(s, opts, now...)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2026 allddd <me@allddd.onl>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package systemdtime_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	systemdtime "gitlab.com/allddd/go-systemd-time"
+)
+
+func TestParser(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	p := systemdtime.NewParser()
+	cases := []struct {
+		input     string
+		expect    time.Time
+		expectErr bool
+	}{
+		{"2009-11-10 18:15:22 America/New_York", time.Date(2009, 11, 10, 23, 15, 22, 0, time.UTC), false},
+		{"2009-11-10 18:15:22 America/New_York", time.Date(2009, 11, 10, 23, 15, 22, 0, time.UTC), false},
+		{"2009-11-10 Asia/Tokyo", time.Date(2009, 11, 9, 15, 0, 0, 0, time.UTC), false},
+		{"today Europe/London", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"+1h", now.Add(systemdtime.Hour), false},
+		{"2009-11-10 18:15:22 Not/TZ", time.Time{}, true},
+		{"2009-11-10 18:15:22 Not/TZ", time.Time{}, true},
+	}
+	for _, tc := range cases {
+		got, err := p.ParseTimestamp(tc.input, now)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	// cached locations are shared
+	a, _ := p.ParseTimestamp("2009-11-10 America/New_York", now)
+	b, _ := p.ParseTimestamp("2009-11-11 America/New_York", now)
+	if a.Location() != b.Location() {
+		t.Errorf("expected cached location, got %p and %p", a.Location(), b.Location())
+	}
+
+	// options are applied
+	got, err := p.ParseTimestampWith("2009-11-10 noon America/New_York", systemdtime.ParseTimestampOptions{Lenient: true}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := time.Date(2009, 11, 10, 17, 0, 0, 0, time.UTC); !got.Equal(expect) {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+
+	// concurrent use
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.ParseTimestamp("2009-11-10 18:15:22 Europe/Berlin", now); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkParser(b *testing.B) {
+	input := "2009-11-10 18:15:22 America/New_York"
+	b.Run("ParseTimestamp", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			systemdtime.ParseTimestamp(input)
+		}
+	})
+	b.Run("Parser", func(b *testing.B) {
+		p := systemdtime.NewParser()
+		b.ReportAllocs()
+		for b.Loop() {
+			p.ParseTimestamp(input)
+		}
+	})
+}
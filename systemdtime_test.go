@@ -24,7 +24,9 @@
 package systemdtime_test
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -32,10 +34,11 @@ import (
 )
 
 var (
-	tzLondon, _  = time.LoadLocation("Europe/London")
-	tzNewYork, _ = time.LoadLocation("America/New_York")
-	tzSydney, _  = time.LoadLocation("Australia/Sydney")
-	tzTokyo, _   = time.LoadLocation("Asia/Tokyo")
+	tzAmsterdam, _ = time.LoadLocation("Europe/Amsterdam")
+	tzLondon, _    = time.LoadLocation("Europe/London")
+	tzNewYork, _   = time.LoadLocation("America/New_York")
+	tzSydney, _    = time.LoadLocation("Australia/Sydney")
+	tzTokyo, _     = time.LoadLocation("Asia/Tokyo")
 )
 
 func TestParseTimespan(t *testing.T) {
@@ -94,10 +97,71 @@ func TestParseTimespan(t *testing.T) {
 		{"2.5d 1.5hours", time.Duration(2.5*float64(systemdtime.Day)) + time.Duration(1.5*float64(systemdtime.Hour)), false},
 		{"1.5h 30min", time.Duration(1.5*float64(systemdtime.Hour)) + 30*systemdtime.Minute, false},
 		{"2.5 d 12h 30min", time.Duration(2.5*float64(systemdtime.Day)) + 12*systemdtime.Hour + 30*systemdtime.Minute, false},
+		{"1.5h 20m", 110 * systemdtime.Minute, false},
+		{"2.25h 45m", 180 * systemdtime.Minute, false},
+		{"45m 2.25h", 180 * systemdtime.Minute, false},
+		{"1.5 5min", 1500*systemdtime.Millisecond + 5*systemdtime.Minute, false},
+		{"5min 1.5", 5*systemdtime.Minute + 1500*systemdtime.Millisecond, false},
+		{"1.5M 0.5y", time.Duration(1.5*float64(systemdtime.Month)) + systemdtime.Year/2, false},
+		{"0.001ms 1.5us", 2500 * systemdtime.Nanosecond, false},
+		// infinity
+		{"infinity", systemdtime.Infinity, false},
+		{" infinity", systemdtime.Infinity, false},
+		{"infinity  ", systemdtime.Infinity, false},
+		{"infinity 5s", 0, true},
+		{"5s infinity", 0, true},
+		{"Infinity", 0, true},
+		{"2xinfinity", 0, true},
+		// any order
+		{"30min 2h", 2*systemdtime.Hour + 30*systemdtime.Minute, false},
+		{"30s 2h 5d 1y", 30*systemdtime.Second + 2*systemdtime.Hour + 5*systemdtime.Day + systemdtime.Year, false},
+		{"1y 5d 2h 30s", 30*systemdtime.Second + 2*systemdtime.Hour + 5*systemdtime.Day + systemdtime.Year, false},
+		{"500ms 10s", 10*systemdtime.Second + 500*systemdtime.Millisecond, false},
+		{"1ns 1us 1ms 1s 1min 1h", systemdtime.Hour + systemdtime.Minute + systemdtime.Second + systemdtime.Millisecond + systemdtime.Microsecond + systemdtime.Nanosecond, false},
+		{"5s 5s", 10 * systemdtime.Second, false},
 		// default unit
 		{"60", 60 * systemdtime.Second, false},
 		{"1.5", 1500 * systemdtime.Millisecond, false},
 		{"60 5min", 60*systemdtime.Second + 5*systemdtime.Minute, false},
+		{"5min 30", 5*systemdtime.Minute + 30*systemdtime.Second, false},
+		{"1h 30 15s", systemdtime.Hour + 45*systemdtime.Second, false},
+		{"60 5min 30", 6*systemdtime.Minute + 30*systemdtime.Second, false},
+		// multiplier
+		{"3x30s", 90 * systemdtime.Second, false},
+		{"2x1.5h", 3 * systemdtime.Hour, false},
+		{"1x5min", 5 * systemdtime.Minute, false},
+		{"3x 30s", 90 * systemdtime.Second, false},
+		{" 3x30s ", 90 * systemdtime.Second, false},
+		{"10x", 0, true},
+		{"3x", 0, true},
+		{"0x30s", 0, true},
+		{"1.5x30s", 0, true},
+		{"3x30s 5min", 0, true},
+		{"3x3x30s", 0, true},
+		{"x30s", 0, true},
+		{"300000x300000h", 0, true},
+		// sign
+		{"+5h", 5 * systemdtime.Hour, false},
+		{"-5h", -5 * systemdtime.Hour, false},
+		{"+30min", 30 * systemdtime.Minute, false},
+		{"- 5m", -5 * systemdtime.Minute, false},
+		{" -1h 30min", -90 * systemdtime.Minute, false},
+		{"-3x30s", -90 * systemdtime.Second, false},
+		{"-0", 0, false},
+		{"-infinity", -systemdtime.Infinity, false},
+		{"-90s", -90 * systemdtime.Second, false},
+		{"-2h30min", -150 * systemdtime.Minute, false},
+		{"-1.5h", -90 * systemdtime.Minute, false},
+		{"5h -10min", 0, true},
+		{"-5h +10min", 0, true},
+		{"-5h -10min", 0, true},
+		{"+", 0, true},
+		{"-", 0, true},
+		{"+ ", 0, true},
+		{"++5h", 0, true},
+		{"+-5h", 0, true},
+		{"- -5h", 0, true},
+		{"5h-", 0, true},
 		// zero
 		{"0", 0, false},
 		{"0s", 0, false},
@@ -170,6 +234,462 @@ func ExampleParseTimespan() {
 	// There are 9040 seconds in "2h30min40seconds".
 }
 
+func TestErrUnexpectedCharacter(t *testing.T) {
+	cases := []struct {
+		input      string
+		unexpected bool
+		pos        string
+	}{
+		{"{{x}}30s", true, "position 0"},
+		{"{{.Timeout}}30s", true, "position 0"},
+		{"  $30s", true, "position 2"},
+		{"30s {{x}}", true, "position 4"},
+		{"5min,10s", false, ""},
+		{"--5min", true, "position 1"},
+		{"- -5m", true, "position 2"},
+		{"+ +5m", true, "position 2"},
+		{"€5", true, "\"€\""},
+		{"5abc", false, ""},
+		{"5 parsecs", false, ""},
+		{"3h -30min", true, "unexpected sign \"-\" at position 3"},
+		{"3h+30min", true, "unexpected sign \"+\" at position 2"},
+	}
+	for _, tc := range cases {
+		_, err := systemdtime.ParseTimespan(tc.input)
+		if err == nil {
+			t.Errorf("%q: expected error, got nil", tc.input)
+			continue
+		}
+		if errors.Is(err, systemdtime.ErrUnexpectedCharacter) != tc.unexpected {
+			t.Errorf("%q: expected ErrUnexpectedCharacter %t, got %v", tc.input, tc.unexpected, err)
+		}
+		if !strings.Contains(err.Error(), tc.pos) {
+			t.Errorf("%q: expected %q in error, got %v", tc.input, tc.pos, err)
+		}
+	}
+}
+
+func TestParseTimespanWithUnitResolver(t *testing.T) {
+	opts := systemdtime.ParseTimespanOptions{
+		UnitResolver: func(name string) (time.Duration, bool) {
+			switch name {
+			case "sprint", "sprints":
+				return 14 * systemdtime.Day, true
+			case "fortnight":
+				return 2 * systemdtime.Week, true
+			case "jiffy":
+				return 10 * systemdtime.Millisecond, true
+			case "h": // built-in units take precedence
+				return systemdtime.Minute, true
+			case "zero":
+				return 0, true
+			}
+			return 0, false
+		},
+	}
+	cases := []struct {
+		input     string
+		expect    time.Duration
+		expectErr bool
+	}{
+		{"1sprint", 14 * systemdtime.Day, false},
+		{"2 sprints 3d", 31 * systemdtime.Day, false},
+		{"0.5sprint", 7 * systemdtime.Day, false},
+		{"1fortnight 1h", 2*systemdtime.Week + systemdtime.Hour, false},
+		{"1.5jiffy", 15 * systemdtime.Millisecond, false},
+		{"2x1sprint", 28 * systemdtime.Day, false},
+		{"3h", 3 * systemdtime.Hour, false},
+		{"1parsec", 0, true},
+		{"1zero", 0, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimespanWith(tc.input, opts)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expect {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	if _, err := systemdtime.ParseTimespan("1sprint"); err == nil {
+		t.Errorf("%q: expected error without UnitResolver, got nil", "1sprint")
+	}
+}
+
+func TestParseTimespanDefaultUnit(t *testing.T) {
+	cases := []struct {
+		input     string
+		unit      time.Duration
+		expect    time.Duration
+		expectErr bool
+	}{
+		{"30", systemdtime.Minute, 30 * systemdtime.Minute, false},
+		{"30s", systemdtime.Minute, 30 * systemdtime.Second, false},
+		{"1.5", systemdtime.Minute, 90 * systemdtime.Second, false},
+		{"1h 30", systemdtime.Minute, 90 * systemdtime.Minute, false},
+		{"2x15", systemdtime.Minute, 30 * systemdtime.Minute, false},
+		{"0", systemdtime.Minute, 0, false},
+		{"30", 0, 30 * systemdtime.Second, false},
+		{"30", -systemdtime.Minute, 0, true},
+		{"30xyz", systemdtime.Minute, 0, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimespanDefaultUnit(tc.input, tc.unit)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expect {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+}
+
+func TestParseTimespanWithCaseInsensitiveUnits(t *testing.T) {
+	opts := systemdtime.ParseTimespanOptions{CaseInsensitiveUnits: true}
+	cases := []struct {
+		input     string
+		expect    time.Duration
+		expectErr bool
+	}{
+		{"5H", 5 * systemdtime.Hour, false},
+		{"5Hr", 5 * systemdtime.Hour, false},
+		{"5MIN", 5 * systemdtime.Minute, false},
+		{"30Min", 30 * systemdtime.Minute, false},
+		{"2Days 1WEEK", 2*systemdtime.Day + systemdtime.Week, false},
+		{"5MS", 5 * systemdtime.Millisecond, false},
+		{"5m", 5 * systemdtime.Minute, false},
+		{"5M", 5 * systemdtime.Month, false},
+		{"5US", 5 * systemdtime.Microsecond, false},
+		{"5 Parsecs", 0, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimespanWith(tc.input, opts)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expect {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	// units are case-sensitive by default
+	for _, input := range []string{"5H", "5MIN", "30Min"} {
+		if _, err := systemdtime.ParseTimespan(input); err == nil {
+			t.Errorf("%q: expected error without CaseInsensitiveUnits, got nil", input)
+		}
+	}
+}
+
+func TestParseTimespanWarn(t *testing.T) {
+	cases := []struct {
+		input     string
+		warnAbove time.Duration
+		expect    time.Duration
+		warn      bool
+		expectErr bool
+	}{
+		{"30s", systemdtime.Minute, 30 * systemdtime.Second, false, false},
+		{"1min", systemdtime.Minute, systemdtime.Minute, false, false},
+		{"1min 1s", systemdtime.Minute, systemdtime.Minute + systemdtime.Second, true, false},
+		{"2w", systemdtime.Day, 2 * systemdtime.Week, true, false},
+		{"0", 0, 0, false, false},
+		{"5xyz", systemdtime.Minute, 0, false, true},
+	}
+	for _, tc := range cases {
+		got, warn, err := systemdtime.ParseTimespanWarn(tc.input, tc.warnAbove)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expect {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+		if warn != tc.warn {
+			t.Errorf("%q: expected warn %t above %v, got %t", tc.input, tc.warn, tc.warnAbove, warn)
+		}
+	}
+}
+
+func TestParseTimespanRange(t *testing.T) {
+	cases := []struct {
+		input     string
+		expectMin time.Duration
+		expectMax time.Duration
+		expectErr bool
+	}{
+		{"30m..2h", 30 * systemdtime.Minute, 2 * systemdtime.Hour, false},
+		{"1h 30min .. 2h", 90 * systemdtime.Minute, 2 * systemdtime.Hour, false},
+		{"5s..5s", 5 * systemdtime.Second, 5 * systemdtime.Second, false},
+		{"0..infinity", 0, systemdtime.Infinity, false},
+		{"2h..30m", 0, 0, true},
+		{"30m", 0, 0, true},
+		{"30m..", 0, 0, true},
+		{"..2h", 0, 0, true},
+		{"30m..2h..3h", 0, 0, true},
+		{"between 30m and 2h", 0, 0, true},
+		{"", 0, 0, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimespanRange(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got.Min != tc.expectMin || got.Max != tc.expectMax {
+			t.Errorf("%q: expected %v..%v, got %v..%v", tc.input, tc.expectMin, tc.expectMax, got.Min, got.Max)
+		}
+	}
+}
+
+func TestParseTimespanRangeWith(t *testing.T) {
+	opts := systemdtime.ParseTimespanOptions{Lenient: true}
+	cases := []struct {
+		input     string
+		expectMin time.Duration
+		expectMax time.Duration
+		expectErr bool
+	}{
+		{"30m..2h", 30 * systemdtime.Minute, 2 * systemdtime.Hour, false},
+		{"between 30m and 2h", 30 * systemdtime.Minute, 2 * systemdtime.Hour, false},
+		{"between 1h 30min and 1d", 90 * systemdtime.Minute, systemdtime.Day, false},
+		{"between 2h and 30m", 0, 0, true},
+		{"between 30m", 0, 0, true},
+		{"between 30m and", 0, 0, true},
+		{"between 30m or 2h", 0, 0, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimespanRangeWith(tc.input, opts)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got.Min != tc.expectMin || got.Max != tc.expectMax {
+			t.Errorf("%q: expected %v..%v, got %v..%v", tc.input, tc.expectMin, tc.expectMax, got.Min, got.Max)
+		}
+	}
+
+	// options apply to both ends
+	got, err := systemdtime.ParseTimespanRangeWith("30..90", systemdtime.ParseTimespanOptions{DefaultUnit: systemdtime.Minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Min != 30*systemdtime.Minute || got.Max != 90*systemdtime.Minute {
+		t.Errorf("expected 30min..90min, got %v..%v", got.Min, got.Max)
+	}
+
+	// error positions refer to the whole range
+	_, err = systemdtime.ParseTimespanRangeWith("between 30m and 2x", opts)
+	var pe *systemdtime.ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+	if pe.Input != "between 30m and 2x" || pe.Pos < 16 {
+		t.Errorf("expected error in whole range, got input %q pos %d", pe.Input, pe.Pos)
+	}
+}
+
+func TestParseHourMinuteDuration(t *testing.T) {
+	cases := []struct {
+		input     string
+		expect    time.Duration
+		expectErr bool
+	}{
+		{"01:30", 90 * systemdtime.Minute, false},
+		{"1:30", 90 * systemdtime.Minute, false},
+		{"25:00", 25 * systemdtime.Hour, false},
+		{"00:00", 0, false},
+		{"100:00:01", 100*systemdtime.Hour + systemdtime.Second, false},
+		{"0:00:01.5", 1500 * systemdtime.Millisecond, false},
+		{"2562047:47:16.854775807", systemdtime.Infinity, false},
+		{"2562047:47:16.854775808", 0, true},
+		{"9999999999:00", 0, true},
+		{"", 0, true},
+		{"01", 0, true},
+		{"01:", 0, true},
+		{"01:60", 0, true},
+		{"01:30:60", 0, true},
+		{"01:30:", 0, true},
+		{"01:30.5", 0, true},
+		{"01:30:00.", 0, true},
+		{"-01:30", 0, true},
+		{"01:30 ", 0, true},
+		{"1h:30", 0, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseHourMinuteDuration(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expect {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+}
+
+func TestParseDecimalHour(t *testing.T) {
+	cases := []struct {
+		input     string
+		expect    time.Duration
+		expectErr bool
+	}{
+		{"18.5", 18*systemdtime.Hour + 30*systemdtime.Minute, false},
+		{"18.25", 18*systemdtime.Hour + 15*systemdtime.Minute, false},
+		{"18", 18 * systemdtime.Hour, false},
+		{"0", 0, false},
+		{".5", 30 * systemdtime.Minute, false},
+		{"8.3333", 8*systemdtime.Hour + 20*systemdtime.Minute, false},
+		{"0.0001", 0, false},
+		{"0.0002", systemdtime.Second, false},
+		{"23.9998", 23*systemdtime.Hour + 59*systemdtime.Minute + 59*systemdtime.Second, false},
+		{"23.99999", 0, true},
+		{"24", 0, true},
+		{"-1", 0, true},
+		{"", 0, true},
+		{"18.", 0, true},
+		{"18.5h", 0, true},
+		{"18:30", 0, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseDecimalHour(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expect {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+}
+
+func TestParseDayFraction(t *testing.T) {
+	cases := []struct {
+		input     string
+		expect    time.Duration
+		expectErr bool
+	}{
+		{"0.75", 18 * systemdtime.Hour, false},
+		{"0.5", 12 * systemdtime.Hour, false},
+		{".25", 6 * systemdtime.Hour, false},
+		{"0", 0, false},
+		{"0.3333333", 8 * systemdtime.Hour, false},
+		{"0.770833333", 18*systemdtime.Hour + 30*systemdtime.Minute, false},
+		{"0.99999", 23*systemdtime.Hour + 59*systemdtime.Minute + 59*systemdtime.Second, false},
+		{"0.999999999", 0, true},
+		{"1", 0, true},
+		{"1.5", 0, true},
+		{"", 0, true},
+		{"0.75x", 0, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseDayFraction(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expect {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	cases := []struct {
+		input     string
+		count     int
+		interval  time.Duration
+		gap       time.Duration
+		expectErr bool
+	}{
+		{"10/min", 10, systemdtime.Minute, 6 * systemdtime.Second, false},
+		{"1/s", 1, systemdtime.Second, systemdtime.Second, false},
+		{"100/5min", 100, 5 * systemdtime.Minute, 3 * systemdtime.Second, false},
+		{"3 / 1h 30min", 3, 90 * systemdtime.Minute, 30 * systemdtime.Minute, false},
+		{"3/s", 3, systemdtime.Second, 333333333 * systemdtime.Nanosecond, false},
+		{"0/min", 0, 0, 0, true},
+		{"10/0s", 0, 0, 0, true},
+		{"10/", 0, 0, 0, true},
+		{"/min", 0, 0, 0, true},
+		{"10min", 0, 0, 0, true},
+		{"ten/min", 0, 0, 0, true},
+		{"10/fortnight", 0, 0, 0, true},
+	}
+	for _, tc := range cases {
+		count, interval, gap, err := systemdtime.ParseRate(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if count != tc.count || interval != tc.interval || gap != tc.gap {
+			t.Errorf("%q: expected %d, %v, %v, got %d, %v, %v", tc.input, tc.count, tc.interval, tc.gap, count, interval, gap)
+		}
+	}
+}
+
 func TestParseTimestamp(t *testing.T) {
 	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
 	cases := []struct {
@@ -203,6 +723,40 @@ func TestParseTimestamp(t *testing.T) {
 		{"2009-00-01", time.Time{}, true},
 		{"2009-01-00", time.Time{}, true},
 		{"2009-11-32", time.Time{}, true},
+		// week date
+		{"2009-W46-2", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"2009-W01-1", time.Date(2008, 12, 29, 0, 0, 0, 0, time.UTC), false},
+		{"2008-W01-1", time.Date(2007, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{"2010-W01-1", time.Date(2010, 1, 4, 0, 0, 0, 0, time.UTC), false},
+		{"2009-W53-7", time.Date(2010, 1, 3, 0, 0, 0, 0, time.UTC), false},
+		{"2004-W53-6", time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2010-W52-7", time.Date(2011, 1, 2, 0, 0, 0, 0, time.UTC), false},
+		{"Tue 2009-W46-2 18:15:22", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"2009-W46-2T18:15:22Z", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"2010-W53-1", time.Time{}, true},
+		{"2009-W00-1", time.Time{}, true},
+		{"2009-W46-0", time.Time{}, true},
+		{"2009-W46-8", time.Time{}, true},
+		{"2009-W4-2", time.Time{}, true},
+		{"2009-W46", time.Time{}, true},
+		{"2009-W46-22", time.Time{}, true},
+		{"09-W46-2", time.Time{}, true},
+		{"Wed 2009-W46-2", time.Time{}, true},
+		// ordinal date
+		{"2009-314", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"2009-001", time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2009-365", time.Date(2009, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{"2008-366", time.Date(2008, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{"2008-060", time.Date(2008, 2, 29, 0, 0, 0, 0, time.UTC), false},
+		{"2009-060", time.Date(2009, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{"Tue 2009-314 18:15:22", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"2009-314T18:15:22Z", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"2009-314 Asia/Tokyo", time.Date(2009, 11, 10, 0, 0, 0, 0, tzTokyo), false},
+		{"2009-366", time.Time{}, true},
+		{"1900-366", time.Time{}, true},
+		{"2009-000", time.Time{}, true},
+		{"2009-3140", time.Time{}, true},
+		{"09-314", time.Time{}, true},
 		// time
 		{"18:15:22", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
 		{"18:15", time.Date(2009, 11, 10, 18, 15, 0, 0, time.UTC), false},
@@ -247,6 +801,13 @@ func TestParseTimestamp(t *testing.T) {
 		{"Friday", time.Time{}, true},
 		{"Fri 18:15:22", time.Time{}, true},
 		{"Tue Tue 2009-11-10", time.Time{}, true},
+		{"Sat Sun 2009-11-14", time.Date(2009, 11, 14, 0, 0, 0, 0, time.UTC), false},
+		{"Sat Sun 2009-11-15", time.Date(2009, 11, 15, 0, 0, 0, 0, time.UTC), false},
+		{"Mon Tuesday wed 2009-11-11 18:15:22", time.Date(2009, 11, 11, 18, 15, 22, 0, time.UTC), false},
+		{"Mon Tue 2009-11-14", time.Time{}, true},
+		{"Sat Sun", time.Time{}, true},
+		{"Sat Sun 18:15:22", time.Time{}, true},
+		{"Sat Sun Sat 2009-11-14", time.Time{}, true},
 		// fractional
 		{"2009-11-10 18:15:22.5", time.Date(2009, 11, 10, 18, 15, 22, 500000000, time.UTC), false},
 		{"2009-11-10 18:15:22.123456", time.Date(2009, 11, 10, 18, 15, 22, 123456000, time.UTC), false},
@@ -279,14 +840,36 @@ func TestParseTimestamp(t *testing.T) {
 		{"2009-11-10 22:02:15Z", time.Date(2009, 11, 10, 22, 2, 15, 0, time.UTC), false},
 		{"2009-11-10Z", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
 		{"2009-11-10+01:00", time.Date(2009, 11, 10, 0, 0, 0, 0, time.FixedZone("", 3600)), false},
+		{"2009-11-10+0100", time.Date(2009, 11, 10, 0, 0, 0, 0, time.FixedZone("", 3600)), false},
+		{"2009-11-10+01", time.Date(2009, 11, 10, 0, 0, 0, 0, time.FixedZone("", 3600)), false},
+		{"2009-11-10-0530", time.Date(2009, 11, 10, 0, 0, 0, 0, time.FixedZone("", -5*3600-30*60)), false},
+		{"2009-11-10-05", time.Date(2009, 11, 10, 0, 0, 0, 0, time.FixedZone("", -5*3600)), false},
+		{"2009-11-10.", time.Time{}, true},
+		{"2009-11-10.5", time.Time{}, true},
+		{"2009-11-10 .5", time.Time{}, true},
+		{"2009-11-10T00:00:00.5Z", time.Date(2009, 11, 10, 0, 0, 0, 500000000, time.UTC), false},
 		{"Tue 2009-11-10Z", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
 		{"Tue 2009-11-10+01:00", time.Date(2009, 11, 10, 0, 0, 0, 0, time.FixedZone("", 3600)), false},
+		{"Tue, 2009-11-10", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"Tuesday, 2009-11-10 18:15:22", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"tue,2009-11-10", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"Wed, 2009-11-10", time.Time{}, true},
+		{"Tue,, 2009-11-10", time.Time{}, true},
+		{", 2009-11-10", time.Time{}, true},
 		{"2009-11-10 18:15:22 +05:60", time.Time{}, true},
+		{"1920-01-01 00:00:00 +00:19:32", time.Date(1920, 1, 1, 0, 0, 0, 0, time.FixedZone("", 19*60+32)), false},
+		{"1920-01-01 00:00:00 +00:19:3", time.Time{}, true},
+		{"1920-01-01 00:00:00 +00:19:60", time.Time{}, true},
 		{"2009-11-10 18:15:22 +99:00", time.Time{}, true},
 		{"2009-11-10 18:15:22 Not/TZ", time.Time{}, true},
 		// rfc3339
 		{"2009-11-10T23:02:15", time.Date(2009, 11, 10, 23, 2, 15, 0, time.UTC), false},
 		{"2009-11-10T23:02:15+01:00", time.Date(2009, 11, 10, 23, 2, 15, 0, time.FixedZone("", 3600)), false},
+		{"2009-11-10T18:15:22+0100", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", 3600)), false},
+		{"2009-11-10T18:15:22+01", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", 3600)), false},
+		{"2009-11-10T18:15:22.5-0530", time.Date(2009, 11, 10, 18, 15, 22, 500000000, time.FixedZone("", -5*3600-30*60)), false},
+		{"2009-11-10T18:15-05", time.Date(2009, 11, 10, 18, 15, 0, 0, time.FixedZone("", -5*3600)), false},
+		{"2009-11-10T18:15:22+010", time.Time{}, true},
 		{"2009-11-10T22:02:15Z", time.Date(2009, 11, 10, 22, 2, 15, 0, time.UTC), false},
 		{"2009-11-10T11:12+02:00", time.Date(2009, 11, 10, 11, 12, 0, 0, time.FixedZone("", 2*3600)), false},
 		{"2009-11-10T11:12:13Z", time.Date(2009, 11, 10, 11, 12, 13, 0, time.UTC), false},
@@ -303,7 +886,15 @@ func TestParseTimestamp(t *testing.T) {
 		{"09-11-10T18:15:22Z", time.Time{}, true},
 		// relative
 		{"+3h30min", time.Date(2009, 11, 11, 2, 30, 0, 0, time.UTC), false},
+		{"+3h 30min", time.Date(2009, 11, 11, 2, 30, 0, 0, time.UTC), false},
+		{"+3h -30min", time.Time{}, true},
+		{"-3h +30min", time.Time{}, true},
 		{"-5s", time.Date(2009, 11, 10, 22, 59, 55, 0, time.UTC), false},
+		{"+ 5m", time.Date(2009, 11, 10, 23, 5, 0, 0, time.UTC), false},
+		{"+infinity", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC).Add(systemdtime.Infinity), false},
+		{"-infinity", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC).Add(-systemdtime.Infinity), false},
+		{"- 5m", time.Date(2009, 11, 10, 22, 55, 0, 0, time.UTC), false},
+		{"+  5min 30s", time.Date(2009, 11, 10, 23, 5, 30, 0, time.UTC), false},
 		{"11min ago", time.Date(2009, 11, 10, 22, 49, 0, 0, time.UTC), false},
 		{"1h left", time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
 		{"+", time.Time{}, true},
@@ -313,6 +904,12 @@ func TestParseTimestamp(t *testing.T) {
 		{"abc ago", time.Time{}, true},
 		{"abc left", time.Time{}, true},
 		{"+5s -5s", time.Time{}, true},
+		{"++5h", time.Time{}, true},
+		{"+-5h", time.Time{}, true},
+		{"- -5h", time.Time{}, true},
+		{"-5min ago", time.Time{}, true},
+		{"+5min left", time.Time{}, true},
+		{"@now+-5m", time.Time{}, true},
 		{"+5s UTC", time.Time{}, true},
 		// unix
 		{"@1395716396", time.Unix(1395716396, 0), false},
@@ -327,6 +924,26 @@ func TestParseTimestamp(t *testing.T) {
 		{"@1234 @5678", time.Time{}, true},
 		{"@1.", time.Time{}, true},
 		{"@1.5abc", time.Time{}, true},
+		{"@1395716396.654321 UTC", time.Unix(1395716396, 654321000), false},
+		{"@0.5 Z", time.Unix(0, 500000000), false},
+		{"@0  UTC", time.Unix(0, 0), false},
+		{"@0.5 Europe/London", time.Time{}, true},
+		{"@0 +01:00", time.Time{}, true},
+		{"@0 ", time.Time{}, true},
+		{"@@0 UTC", time.Time{}, true},
+		{"@now", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), false},
+		{"@now+5m", time.Date(2009, 11, 10, 23, 5, 0, 0, time.UTC), false},
+		{"@now-1h", time.Date(2009, 11, 10, 22, 0, 0, 0, time.UTC), false},
+		{"@now+1h 30min UTC", time.Date(2009, 11, 11, 0, 30, 0, 0, time.UTC), false},
+		{"@now+", time.Time{}, true},
+		{"@now5m", time.Time{}, true},
+		{"@now+5xyz", time.Time{}, true},
+		{"@now+5m Asia/Tokyo", time.Time{}, true},
+		{"@@0", time.Unix(0, 0), false},
+		{"@@1395716396.5", time.Unix(1395716396, 500000000), false},
+		{"@@", time.Time{}, true},
+		{"@@@0", time.Time{}, true},
+		{"@@abc", time.Time{}, true},
 		// error
 		{"", time.Time{}, true},
 		{"invalid", time.Time{}, true},
@@ -347,10 +964,1192 @@ func TestParseTimestamp(t *testing.T) {
 			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
 		}
 	}
-}
 
-func BenchmarkParseTimestamp(b *testing.B) {
-	cases := []struct {
+	// a fraction after a bare date is reported as such, not as an unknown timezone
+	for _, input := range []string{"2009-11-10.", "2009-11-10.5"} {
+		_, err := systemdtime.ParseTimestamp(input, now)
+		if err == nil || !strings.Contains(err.Error(), "fractional seconds") {
+			t.Errorf("%q: expected fractional seconds error, got %v", input, err)
+		}
+	}
+
+	// affixed offsets after a bare date or time keep their offset
+	for input, expect := range map[string]int{
+		"2009-11-10+01:00":            3600,
+		"2009-11-10+0100":             3600,
+		"2009-11-10+01":               3600,
+		"2009-11-10-0530":             -5*3600 - 30*60,
+		"2009-11-10T18:15:22+0100":    3600,
+		"2009-11-10T18:15:22+01":      3600,
+		"2009-11-10T18:15:22.5-0530":  -5*3600 - 30*60,
+		"2009-11-10T18:15:22.5+05:30": 5*3600 + 30*60,
+	} {
+		got, err := systemdtime.ParseTimestamp(input, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", input, err)
+			continue
+		}
+		if _, offset := got.Zone(); offset != expect {
+			t.Errorf("%q: expected offset %d, got %d", input, expect, offset)
+		}
+	}
+}
+
+func TestParseTimestampWithDayStart(t *testing.T) {
+	opts := systemdtime.ParseTimestampOptions{DayStart: 6 * systemdtime.Hour}
+	cases := []struct {
+		input     string
+		now       time.Time
+		expect    time.Time
+		expectErr bool
+	}{
+		{"today", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 10, 6, 0, 0, 0, time.UTC), false},
+		{"yesterday", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 9, 6, 0, 0, 0, time.UTC), false},
+		{"tomorrow", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 11, 6, 0, 0, 0, time.UTC), false},
+		{"today", time.Date(2009, 11, 10, 6, 0, 0, 0, time.UTC), time.Date(2009, 11, 10, 6, 0, 0, 0, time.UTC), false},
+		{"today", time.Date(2009, 11, 10, 3, 0, 0, 0, time.UTC), time.Date(2009, 11, 9, 6, 0, 0, 0, time.UTC), false},
+		{"tomorrow", time.Date(2009, 11, 10, 3, 0, 0, 0, time.UTC), time.Date(2009, 11, 10, 6, 0, 0, 0, time.UTC), false},
+		{"today UTC", time.Date(2009, 11, 10, 23, 0, 0, 0, tzTokyo), time.Date(2009, 11, 10, 6, 0, 0, 0, time.UTC), false},
+		{"2009-11-10", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampWith(tc.input, opts, tc.now)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	// periods of the lenient extensions begin at the day start as well
+	lenient := systemdtime.ParseTimestampOptions{DayStart: 4 * systemdtime.Hour, Lenient: true}
+	early := time.Date(2009, 11, 10, 3, 0, 0, 0, time.UTC)
+	late := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	periods := []struct {
+		input  string
+		now    time.Time
+		expect time.Time
+	}{
+		{"today", early, time.Date(2009, 11, 9, 4, 0, 0, 0, time.UTC)},
+		{"start of day", early, time.Date(2009, 11, 9, 4, 0, 0, 0, time.UTC)},
+		{"end of day", early, time.Date(2009, 11, 10, 3, 59, 59, 999999999, time.UTC)},
+		{"start of day", late, time.Date(2009, 11, 10, 4, 0, 0, 0, time.UTC)},
+		{"start of week", time.Date(2009, 11, 9, 3, 0, 0, 0, time.UTC), time.Date(2009, 11, 2, 4, 0, 0, 0, time.UTC)},
+		{"start of month", time.Date(2009, 11, 1, 3, 0, 0, 0, time.UTC), time.Date(2009, 10, 1, 4, 0, 0, 0, time.UTC)},
+		{"end of month", late, time.Date(2009, 12, 1, 3, 59, 59, 999999999, time.UTC)},
+		{"end of month 2009-12-01", late, time.Date(2010, 1, 1, 3, 59, 59, 999999999, time.UTC)},
+		{"start of year 2009", late, time.Date(2009, 1, 1, 4, 0, 0, 0, time.UTC)},
+		{"this day", early, time.Date(2009, 11, 9, 4, 0, 0, 0, time.UTC)},
+		{"next day", early, time.Date(2009, 11, 10, 4, 0, 0, 0, time.UTC)},
+		{"last day", early, time.Date(2009, 11, 8, 4, 0, 0, 0, time.UTC)},
+		{"next week", late, time.Date(2009, 11, 16, 4, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range periods {
+		got, err := systemdtime.ParseTimestampWith(tc.input, lenient, tc.now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q at %v: expected %v, got %v", tc.input, tc.now, tc.expect, got)
+		}
+	}
+
+	for _, ds := range []time.Duration{-systemdtime.Hour, systemdtime.Day} {
+		_, err := systemdtime.ParseTimestampWith("today", systemdtime.ParseTimestampOptions{DayStart: ds})
+		if err == nil {
+			t.Errorf("day start %v: expected error, got nil", ds)
+		}
+	}
+}
+
+func TestParseTimestampLocalEpoch(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, tzTokyo)
+	cases := []struct {
+		input  string
+		expect time.Time
+	}{
+		{"@0", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@@0", time.Date(1970, 1, 1, 0, 0, 0, 0, tzTokyo)},
+		{"@1395716396", time.Date(2014, 3, 25, 2, 59, 56, 0, time.UTC)},
+		{"@@1395716396", time.Date(2014, 3, 25, 2, 59, 56, 0, tzTokyo)},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestamp(tc.input, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+}
+
+func TestParseTimestampWithOffsetInMinutes(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	opts := systemdtime.ParseTimestampOptions{OffsetInMinutes: true}
+	cases := []struct {
+		input     string
+		expect    time.Time
+		expectErr bool
+	}{
+		{"2009-11-10 18:15:22 +330", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", 5*3600+30*60)), false},
+		{"2009-11-10 18:15:22 -60", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", -3600)), false},
+		{"2009-11-10 18:15:22 +0", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"2009-11-10 18:15:22 +1440", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", 24*3600)), false},
+		{"2009-11-10T18:15:22+330", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", 5*3600+30*60)), false},
+		{"2009-11-10+330", time.Date(2009, 11, 10, 0, 0, 0, 0, time.FixedZone("", 5*3600+30*60)), false},
+		{"today +330", time.Date(2009, 11, 11, 0, 0, 0, 0, time.FixedZone("", 5*3600+30*60)), false},
+		{"2009-11-10 18:15:22 +05:30", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", 5*3600+30*60)), false},
+		{"2009-11-10 18:15:22 +0530", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", 8*3600+50*60)), false},
+		{"2009-11-10 18:15:22 +1441", time.Time{}, true},
+		{"2009-11-10 18:15:22 +", time.Time{}, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampWith(tc.input, opts, now)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	// default is still ±HHMM/±HH
+	got, err := systemdtime.ParseTimestamp("2009-11-10 18:15:22 +0330", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, offset := got.Zone(); offset != 3*3600+30*60 {
+		t.Errorf("expected offset %d, got %d", 3*3600+30*60, offset)
+	}
+}
+
+func TestParseTimestampWithAllow2400(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	opts := systemdtime.ParseTimestampOptions{Allow2400: true}
+	cases := []struct {
+		input     string
+		expect    time.Time
+		expectErr bool
+	}{
+		{"2009-11-10T24:00:00Z", time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"2009-11-10T24:00:00+05:00", time.Date(2009, 11, 11, 0, 0, 0, 0, time.FixedZone("", 5*3600)), false},
+		{"2009-11-10T24:00:00-05:00", time.Date(2009, 11, 11, 0, 0, 0, 0, time.FixedZone("", -5*3600)), false},
+		{"2009-11-10 24:00 Asia/Tokyo", time.Date(2009, 11, 11, 0, 0, 0, 0, tzTokyo), false},
+		{"2009-12-31 24:00:00 UTC", time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2009-11-10 24:00:00.0 UTC", time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"24:00", time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"2009-11-10T24:00:01Z", time.Time{}, true},
+		{"2009-11-10T24:30Z", time.Time{}, true},
+		{"2009-11-10T24:00:00.5Z", time.Time{}, true},
+		{"2009-11-10T25:00:00Z", time.Time{}, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampWith(tc.input, opts, now)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	if _, err := systemdtime.ParseTimestamp("2009-11-10T24:00:00Z", now); err == nil {
+		t.Errorf("%q: expected error without Allow2400, got nil", "2009-11-10T24:00:00Z")
+	}
+}
+
+func TestParseTimestampWithUnixFractionUnit(t *testing.T) {
+	cases := []struct {
+		input     string
+		unit      time.Duration
+		expect    time.Time
+		expectErr bool
+	}{
+		// default
+		{"@0.5", 0, time.Unix(0, 500000000), false},
+		{"@0.500", 0, time.Unix(0, 500000000), false},
+		{"@0.05", 0, time.Unix(0, 50000000), false},
+		// millisecond
+		{"@0.5", systemdtime.Millisecond, time.Unix(0, 5000000), false},
+		{"@0.500", systemdtime.Millisecond, time.Unix(0, 500000000), false},
+		{"@0.050", systemdtime.Millisecond, time.Unix(0, 50000000), false},
+		{"@1395716396.999", systemdtime.Millisecond, time.Unix(1395716396, 999000000), false},
+		{"@@0.5", systemdtime.Millisecond, time.Unix(0, 5000000), false},
+		{"@0.1000", systemdtime.Millisecond, time.Time{}, true},
+		{"@0.", systemdtime.Millisecond, time.Time{}, true},
+		// microsecond
+		{"@0.5", systemdtime.Microsecond, time.Unix(0, 5000), false},
+		{"@0.654321", systemdtime.Microsecond, time.Unix(0, 654321000), false},
+		// invalid unit
+		{"@0.5", systemdtime.Second, time.Time{}, true},
+		{"@0.5", -systemdtime.Millisecond, time.Time{}, true},
+	}
+	for _, tc := range cases {
+		opts := systemdtime.ParseTimestampOptions{UnixFractionUnit: tc.unit}
+		got, err := systemdtime.ParseTimestampWith(tc.input, opts, time.Unix(0, 0).UTC())
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q (%v): expected error, got nil", tc.input, tc.unit)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q (%v): unexpected error: %v", tc.input, tc.unit, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q (%v): expected %v, got %v", tc.input, tc.unit, tc.expect, got)
+		}
+	}
+}
+
+func TestWeekdayMismatchMessage(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	_, err := systemdtime.ParseTimestamp("Mon 2009-11-10", now)
+	if expect := `expected weekday Monday for 2009-11-10, got Tuesday in "Mon 2009-11-10"`; err == nil || err.Error() != expect {
+		t.Errorf("expected %q, got %v", expect, err)
+	}
+	_, err = systemdtime.ParseRFC5322Date("Mon, 10 Nov 2009 18:15:22 GMT")
+	if expect := `expected weekday Monday for 2009-11-10, got Tuesday in "Mon, 10 Nov 2009 18:15:22 GMT"`; err == nil || err.Error() != expect {
+		t.Errorf("expected %q, got %v", expect, err)
+	}
+}
+
+func TestParseTimestampWithWeekdayNames(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	opts := systemdtime.ParseTimestampOptions{
+		WeekdayNames: map[time.Weekday]string{
+			time.Monday:  "Montag",
+			time.Tuesday: "Dienstag",
+		},
+	}
+	cases := []struct {
+		input  string
+		expect []string
+	}{
+		{"Mon 2009-11-10", []string{"Montag", "Dienstag"}},
+		{"Sat Mon 2009-11-10", []string{"Saturday or Montag", "Dienstag"}},
+		{"Tue 2009-11-11", []string{"Dienstag", "Wednesday"}},
+		{"Mon Mon 2009-11-09", []string{"Montag twice"}},
+	}
+	for _, tc := range cases {
+		_, err := systemdtime.ParseTimestampWith(tc.input, opts, now)
+		if err == nil {
+			t.Errorf("%q: expected error, got nil", tc.input)
+			continue
+		}
+		for _, name := range tc.expect {
+			if !strings.Contains(err.Error(), name) {
+				t.Errorf("%q: expected %q in error, got %v", tc.input, name, err)
+			}
+		}
+	}
+
+	// names are only used for messages, parsing is unaffected
+	if _, err := systemdtime.ParseTimestampWith("Montag 2009-11-09", opts, now); err == nil {
+		t.Errorf("%q: expected error, got nil", "Montag 2009-11-09")
+	}
+}
+
+func TestParseTimestampWithDefaultUTC(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, tzTokyo)
+	opts := systemdtime.ParseTimestampOptions{DefaultUTC: true}
+	cases := []struct {
+		input  string
+		expect time.Time
+	}{
+		{"2009-11-10", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC)},
+		{"18:15:22", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC)},
+		{"2009-11-10 18:15:22", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC)},
+		{"today", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC)},
+		{"2009-11-10 18:15:22 Asia/Tokyo", time.Date(2009, 11, 10, 18, 15, 22, 0, tzTokyo)},
+		{"2009-11-10+01:00", time.Date(2009, 11, 10, 0, 0, 0, 0, time.FixedZone("", 3600))},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampWith(tc.input, opts, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	// without the option the reference location is used
+	got, err := systemdtime.ParseTimestamp("2009-11-10", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := time.Date(2009, 11, 10, 0, 0, 0, 0, tzTokyo); !got.Equal(expect) {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+}
+
+func TestParseTimestampUnix(t *testing.T) {
+	cases := []struct {
+		input     string
+		unit      time.Duration
+		expect    time.Time
+		expectErr bool
+	}{
+		{"1395716396654321", systemdtime.Microsecond, time.Unix(1395716396, 654321000), false},
+		{"1395716396654", systemdtime.Millisecond, time.Unix(1395716396, 654000000), false},
+		{"1395716396654321987", systemdtime.Nanosecond, time.Unix(1395716396, 654321987), false},
+		{"1395716396", systemdtime.Second, time.Unix(1395716396, 0), false},
+		{"1395716396.5", systemdtime.Second, time.Unix(1395716396, 500000000), false},
+		{"1395716396654321.5", systemdtime.Microsecond, time.Unix(1395716396, 654321500), false},
+		{"0", systemdtime.Microsecond, time.Unix(0, 0), false},
+		{"139571639665", 10 * systemdtime.Millisecond, time.Unix(1395716396, 650000000), false},
+		{"", systemdtime.Microsecond, time.Time{}, true},
+		{"1395716396654321us", systemdtime.Microsecond, time.Time{}, true},
+		{"@1395716396", systemdtime.Second, time.Time{}, true},
+		{"1395716396", systemdtime.Minute, time.Time{}, true},
+		{"1395716396", 7 * systemdtime.Millisecond, time.Time{}, true},
+		{"1395716396", 0, time.Time{}, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampUnix(tc.input, tc.unit)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q (%v): expected error, got nil", tc.input, tc.unit)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q (%v): unexpected error: %v", tc.input, tc.unit, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q (%v): expected %v, got %v", tc.input, tc.unit, tc.expect, got)
+		}
+	}
+}
+
+func TestParseTimestampInLocation(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, tzTokyo) // 09:00 in New York
+	cases := []struct {
+		input  string
+		expect time.Time
+	}{
+		{"2009-11-10", time.Date(2009, 11, 10, 0, 0, 0, 0, tzNewYork)},
+		{"18:15", time.Date(2009, 11, 10, 18, 15, 0, 0, tzNewYork)},
+		{"2009-11-10 18:15:22", time.Date(2009, 11, 10, 18, 15, 22, 0, tzNewYork)},
+		{"today", time.Date(2009, 11, 10, 0, 0, 0, 0, tzNewYork)},
+		{"+1h", now.Add(systemdtime.Hour)},
+		{"2009-11-10T18:15:22Z", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC)},
+		{"2009-11-10 18:15:22 +01:00", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", 3600))},
+		{"2009-11-10 18:15:22 Asia/Tokyo", time.Date(2009, 11, 10, 18, 15, 22, 0, tzTokyo)},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampInLocation(tc.input, tzNewYork, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	if _, err := systemdtime.ParseTimestampInLocation("2009-11-10", nil, now); err == nil {
+		t.Error("nil location: expected error, got nil")
+	}
+}
+
+func TestParseTimestampWithNowTruncate(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 654321000, time.UTC)
+	opts := systemdtime.ParseTimestampOptions{NowTruncate: systemdtime.Second}
+	cases := []struct {
+		input  string
+		expect time.Time
+	}{
+		{"now", time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)},
+		{"+5m", time.Date(2009, 11, 10, 23, 5, 0, 0, time.UTC)},
+		{"1h ago", time.Date(2009, 11, 10, 22, 0, 0, 0, time.UTC)},
+		{"today", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC)},
+		{"18:15:22.5", time.Date(2009, 11, 10, 18, 15, 22, 500000000, time.UTC)},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampWith(tc.input, opts, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	// "now" is not in the past of the truncated reference time
+	opts.RejectPast = true
+	if _, err := systemdtime.ParseTimestampWith("now", opts, now); err != nil {
+		t.Errorf("%q: unexpected error: %v", "now", err)
+	}
+
+	opts = systemdtime.ParseTimestampOptions{NowTruncate: -systemdtime.Second}
+	if _, err := systemdtime.ParseTimestampWith("now", opts, now); err == nil {
+		t.Errorf("%q: expected error, got nil", "now")
+	}
+}
+
+func TestParseTimestampWithRejectFutureAndPast(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	future := systemdtime.ParseTimestampOptions{RejectFuture: true}
+	past := systemdtime.ParseTimestampOptions{RejectPast: true}
+	cases := []struct {
+		input     string
+		opts      systemdtime.ParseTimestampOptions
+		expectErr bool
+	}{
+		{"1989-11-09 UTC", future, false},
+		{"2039-11-09 UTC", future, true},
+		{"now", future, false},
+		{"yesterday", future, false},
+		{"tomorrow", future, true},
+		{"-5min", future, false},
+		{"+5min", future, true},
+		{"5min left", future, true},
+		{"@0", future, false},
+		{"1989-11-09 UTC", past, true},
+		{"2039-11-09 UTC", past, false},
+		{"now", past, false},
+		{"yesterday", past, true},
+		{"tomorrow", past, false},
+		{"5min ago", past, true},
+		{"+5min", past, false},
+		{"@0", past, true},
+	}
+	for _, tc := range cases {
+		_, err := systemdtime.ParseTimestampWith(tc.input, tc.opts, now)
+		if tc.expectErr && err == nil {
+			t.Errorf("%q: expected error, got nil", tc.input)
+		} else if !tc.expectErr && err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+		}
+	}
+}
+
+func TestParseTimestampWithCommaDecimal(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	opts := systemdtime.ParseTimestampOptions{CommaDecimal: true}
+	cases := []struct {
+		input     string
+		expect    time.Time
+		expectErr bool
+	}{
+		{"18:15:22,5", time.Date(2009, 11, 10, 18, 15, 22, 500000000, time.UTC), false},
+		{"2009-11-10 18:15:22,654321", time.Date(2009, 11, 10, 18, 15, 22, 654321000, time.UTC), false},
+		{"2009-11-10T18:15:22,5Z", time.Date(2009, 11, 10, 18, 15, 22, 500000000, time.UTC), false},
+		{"2009-11-10 18:15:22.5", time.Date(2009, 11, 10, 18, 15, 22, 500000000, time.UTC), false},
+		{"18:15:22,", time.Time{}, true},
+		{"18:15:22.", time.Time{}, true},
+		{"18:15,5", time.Time{}, true},
+		{"18:15:22,5,5", time.Time{}, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampWith(tc.input, opts, now)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	// the comma requires the option
+	if _, err := systemdtime.ParseTimestamp("18:15:22,5", now); err == nil {
+		t.Errorf("%q: expected error without CommaDecimal, got nil", "18:15:22,5")
+	}
+}
+
+func TestParseTimestampWithTwoDigitYearPivot(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	pivot := func(p int) systemdtime.ParseTimestampOptions {
+		return systemdtime.ParseTimestampOptions{TwoDigitYearPivot: &p}
+	}
+	cases := []struct {
+		input     string
+		opts      systemdtime.ParseTimestampOptions
+		expect    time.Time
+		expectErr bool
+	}{
+		{"68-01-01", systemdtime.ParseTimestampOptions{}, time.Date(2068, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"69-01-01", systemdtime.ParseTimestampOptions{}, time.Date(1969, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"50-01-01", pivot(50), time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"51-01-01", pivot(50), time.Date(1951, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"00-01-01", pivot(0), time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"01-01-01", pivot(0), time.Date(1901, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"99-01-01", pivot(99), time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"09-11-10 18:15", pivot(5), time.Date(1909, 11, 10, 18, 15, 0, 0, time.UTC), false},
+		{"2009-11-10", pivot(5), time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"00-01-01", pivot(-1), time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"09-11-10", pivot(-1), time.Date(1909, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"09-11-10", pivot(-2), time.Time{}, true},
+		{"09-11-10", pivot(100), time.Time{}, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampWith(tc.input, tc.opts, now)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+}
+
+func TestParseTimestampWithTrimInput(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	opts := systemdtime.ParseTimestampOptions{TrimInput: true}
+	cases := []struct {
+		input  string
+		expect time.Time
+	}{
+		{" 2009-11-10 ", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC)},
+		{" @0 ", time.Unix(0, 0)},
+		{"\t+5min\n", now.Add(5 * time.Minute)},
+		{" now ", now},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampWith(tc.input, opts, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+		if _, err := systemdtime.ParseTimestampWith(tc.input, systemdtime.ParseTimestampOptions{}, now); err == nil {
+			t.Errorf("%q: expected error without TrimInput, got nil", tc.input)
+		}
+	}
+
+	// error positions refer to the untrimmed input
+	_, err := systemdtime.ParseTimestampWith("  2009-11-10 x", opts, now)
+	var pe *systemdtime.ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+	if pe.Input != "  2009-11-10 x" || pe.Pos < 2 {
+		t.Errorf("expected error in untrimmed input, got input %q pos %d", pe.Input, pe.Pos)
+	}
+}
+
+func TestParseTimestampWithAllowedKinds(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	absolute := systemdtime.ParseTimestampOptions{
+		AllowedKinds: []systemdtime.Kind{systemdtime.KindDate, systemdtime.KindDateTime},
+	}
+	relative := systemdtime.ParseTimestampOptions{
+		AllowedKinds: []systemdtime.Kind{systemdtime.KindRelative},
+	}
+	unix := systemdtime.ParseTimestampOptions{
+		AllowedKinds: []systemdtime.Kind{systemdtime.KindUnix},
+	}
+	cases := []struct {
+		input     string
+		opts      systemdtime.ParseTimestampOptions
+		expectErr bool
+	}{
+		{"2009-11-10", absolute, false},
+		{"2009-11-10 18:15:22 UTC", absolute, false},
+		{"Tue 2009-11-10T18:15:22Z", absolute, false},
+		{"18:15:22", absolute, true},
+		{"now", absolute, true},
+		{"today", absolute, true},
+		{"+5h", absolute, true},
+		{"5min ago", absolute, true},
+		{"@1395716396", absolute, true},
+		{"+5h", relative, false},
+		{"5min left", relative, false},
+		{"@now+5m", relative, false},
+		{"@now", relative, false},
+		{"@now", unix, true},
+		{"@1395716396", unix, false},
+		{"now", relative, true},
+		{"2009-11-10", relative, true},
+		{"now", systemdtime.ParseTimestampOptions{}, false},
+	}
+	for _, tc := range cases {
+		_, err := systemdtime.ParseTimestampWith(tc.input, tc.opts, now)
+		if tc.expectErr && err == nil {
+			t.Errorf("%q: expected error, got nil", tc.input)
+		} else if !tc.expectErr && err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+		}
+	}
+}
+
+func TestParseTimestampWithLenient(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	opts := systemdtime.ParseTimestampOptions{Lenient: true}
+	cases := []struct {
+		input     string
+		expect    time.Time
+		expectErr bool
+	}{
+		// percent of day
+		{"50% of today", time.Date(2009, 11, 10, 12, 0, 0, 0, time.UTC), false},
+		{"25% of tomorrow", time.Date(2009, 11, 11, 6, 0, 0, 0, time.UTC), false},
+		{"0% of yesterday", time.Date(2009, 11, 9, 0, 0, 0, 0, time.UTC), false},
+		{"100% of today", time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"12.5% of today", time.Date(2009, 11, 10, 3, 0, 0, 0, time.UTC), false},
+		{"50%  of  today UTC", time.Date(2009, 11, 10, 12, 0, 0, 0, time.UTC), false},
+		{"50% of today Asia/Tokyo", time.Date(2009, 11, 11, 12, 0, 0, 0, tzTokyo), false},
+		{"50% of today America/New_York", time.Date(2009, 11, 10, 12, 0, 0, 0, tzNewYork), false},
+		{"101% of today", time.Time{}, true},
+		{"50% of now", time.Time{}, true},
+		{"50% today", time.Time{}, true},
+		{"50%of today", time.Time{}, true},
+		{"50% of", time.Time{}, true},
+		{"50%", time.Time{}, true},
+		// end of period
+		{"end of day", time.Date(2009, 11, 10, 23, 59, 59, 999999999, time.UTC), false},
+		{"end of week", time.Date(2009, 11, 15, 23, 59, 59, 999999999, time.UTC), false},
+		{"end of month", time.Date(2009, 11, 30, 23, 59, 59, 999999999, time.UTC), false},
+		{"end of year", time.Date(2009, 12, 31, 23, 59, 59, 999999999, time.UTC), false},
+		{"end of year 2009", time.Date(2009, 12, 31, 23, 59, 59, 999999999, time.UTC), false},
+		{"end of year of 1999", time.Date(1999, 12, 31, 23, 59, 59, 999999999, time.UTC), false},
+		{"end of month 2008-02-10", time.Date(2008, 2, 29, 23, 59, 59, 999999999, time.UTC), false},
+		{"end of month of 2009-02-10 Asia/Tokyo", time.Date(2009, 2, 28, 23, 59, 59, 999999999, tzTokyo), false},
+		{"end of week 2009-11-16", time.Date(2009, 11, 22, 23, 59, 59, 999999999, time.UTC), false},
+		{"end of week 2009-11-15", time.Date(2009, 11, 15, 23, 59, 59, 999999999, time.UTC), false},
+		{"end of day tomorrow", time.Date(2009, 11, 11, 23, 59, 59, 999999999, time.UTC), false},
+		{"end of fortnight", time.Time{}, true},
+		// hour cycle
+		{"18:15:22 (24h)", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"00:15 (24h)", time.Date(2009, 11, 10, 0, 15, 0, 0, time.UTC), false},
+		{"6:15:22 PM (12h)", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"2009-11-10 06:15:22 am UTC (12h)", time.Date(2009, 11, 10, 6, 15, 22, 0, time.UTC), false},
+		{"12:00 PM(12h)", time.Date(2009, 11, 10, 12, 0, 0, 0, time.UTC), false},
+		{"12:30 AM (12h)", time.Date(2009, 11, 10, 0, 30, 0, 0, time.UTC), false},
+		{"2009-11-10 noon (12h)", time.Date(2009, 11, 10, 12, 0, 0, 0, time.UTC), false},
+		{"6:15 (12h)", time.Time{}, true},
+		{"13:00 (12h)", time.Time{}, true},
+		{"13:00 PM (12h)", time.Time{}, true},
+		{"00:15 AM (12h)", time.Time{}, true},
+		{"6:15 PM (24h)", time.Time{}, true},
+		{"6:15 PM", time.Time{}, true},
+		{"2009-11-10 (24h)", time.Time{}, true},
+		{"18:15 (36h)", time.Time{}, true},
+		{"(24h)", time.Time{}, true},
+		// next, last, or this period
+		{"next week", time.Date(2009, 11, 16, 0, 0, 0, 0, time.UTC), false},
+		{"last week", time.Date(2009, 11, 2, 0, 0, 0, 0, time.UTC), false},
+		{"this week", time.Date(2009, 11, 9, 0, 0, 0, 0, time.UTC), false},
+		{"next day", time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), false},
+		{"last day", time.Date(2009, 11, 9, 0, 0, 0, 0, time.UTC), false},
+		{"next month", time.Date(2009, 12, 1, 0, 0, 0, 0, time.UTC), false},
+		{"last month", time.Date(2009, 10, 1, 0, 0, 0, 0, time.UTC), false},
+		{"this month", time.Date(2009, 11, 1, 0, 0, 0, 0, time.UTC), false},
+		{"next year", time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"last year", time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"this year", time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"next  week", time.Date(2009, 11, 16, 0, 0, 0, 0, time.UTC), false},
+		{"next fortnight", time.Time{}, true},
+		{"next week UTC", time.Time{}, true},
+		{"next", time.Time{}, true},
+		{"next ", time.Time{}, true},
+		{"nextweek", time.Time{}, true},
+		// start of period
+		{"start of day", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"start of week", time.Date(2009, 11, 9, 0, 0, 0, 0, time.UTC), false},
+		{"start of month", time.Date(2009, 11, 1, 0, 0, 0, 0, time.UTC), false},
+		{"start of year", time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"start of year 2009", time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"start of month of 2008-02-29 18:15:22", time.Date(2008, 2, 1, 0, 0, 0, 0, time.UTC), false},
+		{"start of week 2009-11-15", time.Date(2009, 11, 9, 0, 0, 0, 0, time.UTC), false},
+		{"start of week 2009-11-16", time.Date(2009, 11, 16, 0, 0, 0, 0, time.UTC), false},
+		{"start of fortnight", time.Time{}, true},
+		{"start of", time.Time{}, true},
+		{"end of", time.Time{}, true},
+		{"end of month 2009", time.Time{}, true},
+		{"end of year abc", time.Time{}, true},
+		// calendar tag
+		{"1700-02-29 (OS)", time.Date(1700, 3, 11, 0, 0, 0, 0, time.UTC), false},
+		{"1582-10-04 (OS)", time.Date(1582, 10, 14, 0, 0, 0, 0, time.UTC), false},
+		{"1752-09-02 12:00 UTC (OS)", time.Date(1752, 9, 13, 12, 0, 0, 0, time.UTC), false},
+		{"Thu 1582-10-04 (OS)", time.Date(1582, 10, 14, 0, 0, 0, 0, time.UTC), false},
+		{"2009-11-10(NS)", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"1700-02-30 (OS)", time.Time{}, true},
+		{"1701-02-29 (OS)", time.Time{}, true},
+		{"Fri 1582-10-04 (OS)", time.Time{}, true},
+		{"18:15 (OS)", time.Time{}, true},
+		{"(OS)", time.Time{}, true},
+		// signed year
+		{"+2009-11-10", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"+12009-11-10 18:15:22", time.Date(12009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"-0044-03-15", time.Date(-44, 3, 15, 0, 0, 0, 0, time.UTC), false},
+		{"+0000-01-01T00:00:00Z", time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"+09-11-10", time.Time{}, true},
+		{"-2009min", time.Date(2009, 11, 9, 13, 31, 0, 0, time.UTC), false},
+		// abbreviation after offset
+		{"2009-11-10 18:15:22 +05:30 IST", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", 5*3600+30*60)), false},
+		{"2009-11-10 18:15:22-0500 EST", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", -5*3600)), false},
+		{"2009-11-10+01:00 CET", time.Date(2009, 11, 10, 0, 0, 0, 0, time.FixedZone("", 3600)), false},
+		{"2009-11-10 18:15:22 +05:30 IST UTC", time.Time{}, true},
+		{"2009-11-10 18:15:22 +05:30 1ST", time.Time{}, true},
+		{"2009-11-10 18:15:22 UTC GMT", time.Time{}, true},
+		{"2009-11-10 18:15:22 Asia/Kolkata IST", time.Time{}, true},
+		// era
+		{"44-03-15 BC", time.Date(-43, 3, 15, 0, 0, 0, 0, time.UTC), false},
+		{"0044-03-15 BCE", time.Date(-43, 3, 15, 0, 0, 0, 0, time.UTC), false},
+		{"1-01-01 BC", time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2009-11-10 AD", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"9-11-10 CE", time.Date(9, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"44-03-15 12:00 BC", time.Date(-43, 3, 15, 12, 0, 0, 0, time.UTC), false},
+		{"44-03-15 BC (OS)", time.Date(-43, 3, 13, 0, 0, 0, 0, time.UTC), false},
+		{"0-01-01 BC", time.Time{}, true},
+		{"-0044-03-15 BC", time.Time{}, true},
+		{"18:15 AD", time.Time{}, true},
+		{"2009-11-10AD", time.Time{}, true},
+		// time of day as a word
+		{"2009-11-10 noon", time.Date(2009, 11, 10, 12, 0, 0, 0, time.UTC), false},
+		{"2009-11-10 midnight", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"2009-11-10 Noon UTC", time.Date(2009, 11, 10, 12, 0, 0, 0, time.UTC), false},
+		{"2009-11-10 noon +01:00", time.Date(2009, 11, 10, 12, 0, 0, 0, time.FixedZone("", 3600)), false},
+		{"2009-11-10 noon 12:00", time.Time{}, true},
+		{"2009-11-10 noonish", time.Time{}, true},
+		{"noon", time.Time{}, true},
+		// unaffected
+		{"2009-11-10 18:15:22", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampWith(tc.input, opts, now)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	// extensions require the lenient option
+	for _, input := range []string{"50% of today", "start of month", "end of month", "next week", "1700-02-29 (OS)", "+2009-11-10", "18:15 +05:30 IST", "2009-11-10 noon", "44-03-15 BC", "18:15:22 (24h)"} {
+		if _, err := systemdtime.ParseTimestamp(input, now); err == nil {
+			t.Errorf("%q: expected error without Lenient, got nil", input)
+		}
+	}
+}
+
+func TestParseTimestampFields(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	cases := []struct {
+		input  string
+		expect systemdtime.Fields
+	}{
+		{"now", systemdtime.Fields{Time: now}},
+		{"today", systemdtime.Fields{Time: time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC)}},
+		{"today UTC", systemdtime.Fields{Time: time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), HasZone: true}},
+		{"2009-11-10", systemdtime.Fields{Time: time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), HasDate: true}},
+		{"18:15", systemdtime.Fields{Time: time.Date(2009, 11, 10, 18, 15, 0, 0, time.UTC), HasTime: true}},
+		{"18:15:22.0 UTC", systemdtime.Fields{Time: time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), HasTime: true, HasFraction: true, HasZone: true}},
+		{"2009-11-10Z", systemdtime.Fields{Time: time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), HasDate: true, HasZone: true}},
+		{
+			"Tue 2009-11-10T18:15:22.5+01:00",
+			systemdtime.Fields{
+				Time:    time.Date(2009, 11, 10, 18, 15, 22, 500000000, time.FixedZone("", 3600)),
+				HasDate: true, HasTime: true, HasZone: true, HasWeekday: true, HasFraction: true,
+			},
+		},
+		{"+5h", systemdtime.Fields{Time: now.Add(5 * systemdtime.Hour), IsRelative: true}},
+		{"5min ago", systemdtime.Fields{Time: now.Add(-5 * systemdtime.Minute), IsRelative: true}},
+		{"@1395716396", systemdtime.Fields{Time: time.Unix(1395716396, 0), IsUnix: true}},
+		{"@1395716396.5", systemdtime.Fields{Time: time.Unix(1395716396, 500000000), IsUnix: true, HasFraction: true}},
+		{"@1395716396.5 UTC", systemdtime.Fields{Time: time.Unix(1395716396, 500000000), IsUnix: true, HasFraction: true, HasZone: true}},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampFields(tc.input, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Time.Equal(tc.expect.Time) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect.Time, got.Time)
+		}
+		got.Time = tc.expect.Time
+		if got != tc.expect {
+			t.Errorf("%q: expected %+v, got %+v", tc.input, tc.expect, got)
+		}
+	}
+
+	if _, err := systemdtime.ParseTimestampFields("invalid", now); err == nil {
+		t.Errorf("%q: expected error, got nil", "invalid")
+	}
+}
+
+func TestFieldsKind(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	cases := []struct {
+		input  string
+		expect systemdtime.Kind
+	}{
+		{"now", systemdtime.KindToken},
+		{"today UTC", systemdtime.KindToken},
+		{"2009-11-10", systemdtime.KindDate},
+		{"2009-11-10 UTC", systemdtime.KindDate},
+		{"18:15", systemdtime.KindTime},
+		{"2009-11-10T18:15:22Z", systemdtime.KindDateTime},
+		{"+5h", systemdtime.KindRelative},
+		{"5min ago", systemdtime.KindRelative},
+		{"@now-5m", systemdtime.KindRelative},
+		{"@now", systemdtime.KindRelative},
+		{"@now UTC", systemdtime.KindRelative},
+		{"@1395716396.5 UTC", systemdtime.KindUnix},
+	}
+	for _, tc := range cases {
+		f, err := systemdtime.ParseTimestampFields(tc.input, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got := f.Kind(); got != tc.expect {
+			t.Errorf("%q: expected %s, got %s", tc.input, tc.expect, got)
+		}
+	}
+}
+
+func TestParseTimeOrSpan(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	cases := []struct {
+		input      string
+		expect     time.Time
+		expectSpan time.Duration
+		expectKind systemdtime.Kind
+	}{
+		{"2009-11-10 18:15:22", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), 0, systemdtime.KindDateTime},
+		{"2009-11-10", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), 0, systemdtime.KindDate},
+		{"18:15", time.Date(2009, 11, 10, 18, 15, 0, 0, time.UTC), 0, systemdtime.KindTime},
+		{"now", now, 0, systemdtime.KindToken},
+		{"tomorrow", time.Date(2009, 11, 11, 0, 0, 0, 0, time.UTC), 0, systemdtime.KindToken},
+		{"@1395716396", time.Unix(1395716396, 0), 0, systemdtime.KindUnix},
+		{"+2h", time.Time{}, 2 * systemdtime.Hour, systemdtime.KindRelative},
+		{"-1h 30min", time.Time{}, -90 * systemdtime.Minute, systemdtime.KindRelative},
+		{"5min ago", time.Time{}, -5 * systemdtime.Minute, systemdtime.KindRelative},
+		{"3d left", time.Time{}, 3 * systemdtime.Day, systemdtime.KindRelative},
+		{"@now+5m", time.Time{}, 5 * systemdtime.Minute, systemdtime.KindRelative},
+		{"@now", time.Time{}, 0, systemdtime.KindRelative},
+	}
+	for _, tc := range cases {
+		got, span, kind, err := systemdtime.ParseTimeOrSpan(tc.input, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) || span != tc.expectSpan || kind != tc.expectKind {
+			t.Errorf("%q: expected %v, %v, %s, got %v, %v, %s", tc.input, tc.expect, tc.expectSpan, tc.expectKind, got, span, kind)
+		}
+	}
+
+	if _, _, _, err := systemdtime.ParseTimeOrSpan("+5 parsecs", now); err == nil {
+		t.Errorf("%q: expected error, got nil", "+5 parsecs")
+	}
+}
+
+func TestTraceTimestamp(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	cases := []struct {
+		input     string
+		expect    []string
+		expectErr bool
+	}{
+		{"Tue 2009-11-10 18:15:22 UTC", []string{"matched weekday Tue", "parsed date 2009-11-10", "parsed time 18:15:22", "parsed zone UTC"}, false},
+		{"2009-11-10+01:00", []string{"parsed date 2009-11-10", "parsed zone +01:00"}, false},
+		{"18:15", []string{"parsed time 18:15"}, false},
+		{"now", []string{"matched token now"}, false},
+		{"tomorrow", []string{"matched token tomorrow"}, false},
+		{"@1234567890", []string{"matched unix timestamp"}, false},
+		{"5min ago", []string{"parsed relative time span -5m0s"}, false},
+		{"+1h", []string{"parsed relative time span +1h0m0s"}, false},
+		{"Wed 2009-11-10", []string{"matched weekday Wed", "parsed date 2009-11-10"}, true},
+		{"2009-11-10 25:00", []string{"parsed date 2009-11-10"}, true},
+	}
+	for _, tc := range cases {
+		_, got, err := systemdtime.TraceTimestamp(tc.input, now)
+		if tc.expectErr && err == nil {
+			t.Errorf("%q: expected error, got nil", tc.input)
+		} else if !tc.expectErr && err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+		}
+		if strings.Join(got, "; ") != strings.Join(tc.expect, "; ") {
+			t.Errorf("%q: expected trace %q, got %q", tc.input, tc.expect, got)
+		}
+	}
+
+	// the result is the same as without tracing
+	got, _, err := systemdtime.TraceTimestamp("Tue 2009-11-10 18:15:22 UTC", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC); !got.Equal(expect) {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+}
+
+func TestParseTimestampPrecision2(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	cases := []struct {
+		input  string
+		expect time.Duration
+	}{
+		{"2009-11-10", systemdtime.Day},
+		{"Tue 2009-11-10 UTC", systemdtime.Day},
+		{"today", systemdtime.Day},
+		{"18:15", systemdtime.Minute},
+		{"2009-11-10 18:15 +01:00", systemdtime.Minute},
+		{"18:15:22", systemdtime.Second},
+		{"2009-11-10T18:15:22Z", systemdtime.Second},
+		{"18:15:22.5", 100 * systemdtime.Millisecond},
+		{"18:15:22.654 UTC", systemdtime.Millisecond},
+		{"2009-11-10T18:15:22.654321+01:00", systemdtime.Microsecond},
+		{"18:15:22.123456789", systemdtime.Nanosecond},
+		{"18:15:22.1234567891", systemdtime.Nanosecond},
+		{"@1395716396", systemdtime.Second},
+		{"@1395716396.65", 10 * systemdtime.Millisecond},
+		{"now", systemdtime.Nanosecond},
+		{"+5h", systemdtime.Nanosecond},
+		{"@now", systemdtime.Nanosecond},
+		{"@now UTC", systemdtime.Nanosecond},
+		{"@now-5m", systemdtime.Nanosecond},
+		{"@now+1.5s", systemdtime.Nanosecond},
+	}
+	for _, tc := range cases {
+		_, got, err := systemdtime.ParseTimestampPrecision2(tc.input, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expect {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	if _, _, err := systemdtime.ParseTimestampPrecision2("invalid", now); err == nil {
+		t.Errorf("%q: expected error, got nil", "invalid")
+	}
+}
+
+func TestOffsetForZone(t *testing.T) {
+	gmtPlus5, err := time.LoadLocation("Etc/GMT+5")
+	if err != nil {
+		t.Skip(err)
+	}
+	gmtMinus3, err := time.LoadLocation("Etc/GMT-3")
+	if err != nil {
+		t.Skip(err)
+	}
+	at := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	cases := []struct {
+		loc    *time.Location
+		at     time.Time
+		expect int
+	}{
+		{gmtPlus5, at, -5 * 3600},
+		{gmtMinus3, at, 3 * 3600},
+		{time.UTC, at, 0},
+		{tzNewYork, at, -5 * 3600},
+		{tzNewYork, time.Date(2009, 7, 10, 23, 0, 0, 0, time.UTC), -4 * 3600},
+		{time.FixedZone("", 5*3600+30*60), at, 5*3600 + 30*60},
+	}
+	for _, tc := range cases {
+		if got := systemdtime.OffsetForZone(tc.loc, tc.at); got != tc.expect {
+			t.Errorf("%s at %v: expected %d, got %d", tc.loc, tc.at, tc.expect, got)
+		}
+	}
+
+	// the inverted sign also applies when parsing
+	got, err := systemdtime.ParseTimestamp("2009-11-10 18:15:22 Etc/GMT+5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := time.Date(2009, 11, 10, 23, 15, 22, 0, time.UTC); !got.Equal(expect) {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+	if offset := systemdtime.OffsetForZone(got.Location(), got); offset != -5*3600 {
+		t.Errorf("expected offset %d, got %d", -5*3600, offset)
+	}
+}
+
+func TestSpansDSTTransition(t *testing.T) {
+	cases := []struct {
+		start  time.Time
+		end    time.Time
+		spans  bool
+		change time.Duration
+	}{
+		// spring forward (2009-03-29 01:00 UTC in London)
+		{time.Date(2009, 3, 28, 12, 0, 0, 0, tzLondon), time.Date(2009, 3, 29, 12, 0, 0, 0, tzLondon), true, systemdtime.Hour},
+		// fall back (2009-11-01 02:00 in New York)
+		{time.Date(2009, 10, 31, 12, 0, 0, 0, tzNewYork), time.Date(2009, 11, 1, 12, 0, 0, 0, tzNewYork), true, -systemdtime.Hour},
+		// no transition
+		{time.Date(2009, 11, 10, 0, 0, 0, 0, tzLondon), time.Date(2009, 11, 20, 0, 0, 0, 0, tzLondon), false, 0},
+		{time.Date(2009, 1, 1, 0, 0, 0, 0, tzTokyo), time.Date(2009, 12, 31, 0, 0, 0, 0, tzTokyo), false, 0},
+		{time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2009, 12, 31, 0, 0, 0, 0, time.UTC), false, 0},
+		// transitions cancel out
+		{time.Date(2009, 1, 1, 0, 0, 0, 0, tzLondon), time.Date(2009, 12, 31, 0, 0, 0, 0, tzLondon), true, 0},
+		// end before start
+		{time.Date(2009, 3, 29, 12, 0, 0, 0, tzLondon), time.Date(2009, 3, 28, 12, 0, 0, 0, tzLondon), false, 0},
+	}
+	for _, tc := range cases {
+		spans, change := systemdtime.SpansDSTTransition(tc.start, tc.end)
+		if spans != tc.spans || change != tc.change {
+			t.Errorf("%v - %v: expected %t, %v, got %t, %v", tc.start, tc.end, tc.spans, tc.change, spans, change)
+		}
+	}
+}
+
+func TestParseTimestampClamped(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	earliest := time.Unix(0, 0)
+	latest := time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+	cases := []struct {
+		input     string
+		expect    time.Time
+		clamped   bool
+		expectErr bool
+	}{
+		{"1969-12-31 23:59:59 UTC", earliest, true, false},
+		{"1970-01-01 00:00:00 UTC", earliest, false, false},
+		{"2009-11-10 18:15:22 UTC", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false, false},
+		{"9999-12-31 23:59:59.5 UTC", latest, true, false},
+		{"-50y", earliest, true, false},
+		{"now", now, false, false},
+		{"invalid", time.Time{}, false, true},
+	}
+	for _, tc := range cases {
+		got, clamped, err := systemdtime.ParseTimestampClamped(tc.input, earliest, latest, now)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+		if clamped != tc.clamped {
+			t.Errorf("%q: expected clamped %t, got %t", tc.input, tc.clamped, clamped)
+		}
+	}
+
+	if _, _, err := systemdtime.ParseTimestampClamped("now", latest, earliest, now); err == nil {
+		t.Errorf("inverted range: expected error, got nil")
+	}
+	if _, _, err := systemdtime.ParseTimestampClamped("now", earliest, earliest, now); err == nil {
+		t.Errorf("empty range: expected error, got nil")
+	}
+}
+
+func TestParseTimestampToward(t *testing.T) {
+	from := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	to := from.Add(4 * systemdtime.Hour)
+	cases := []struct {
+		input     string
+		expect    time.Time
+		expectErr bool
+	}{
+		{"50%", from.Add(2 * systemdtime.Hour), false},
+		{"+50%", from.Add(2 * systemdtime.Hour), false},
+		{"0%", from, false},
+		{"100%", to, false},
+		{"12.5%", from.Add(30 * systemdtime.Minute), false},
+		{"+1h", from.Add(systemdtime.Hour), false},
+		{"5min ago", from.Add(-5 * systemdtime.Minute), false},
+		{"now", from, false},
+		{"2009-11-10 18:15:22 UTC", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"101%", time.Time{}, true},
+		{"-50%", time.Time{}, true},
+		{"50 %", time.Time{}, true},
+		{"50%%", time.Time{}, true},
+		{"%", time.Time{}, true},
+		{"+%", time.Time{}, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseTimestampToward(tc.input, from, to)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	// a deadline before from interpolates backward
+	got, err := systemdtime.ParseTimestampToward("25%", to, from)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := to.Add(-systemdtime.Hour); !got.Equal(expect) {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+
+	// out-of-range percentages report the range
+	_, err = systemdtime.ParseTimestampToward("150%", from, to)
+	if err == nil || !strings.Contains(err.Error(), "range 0-100") {
+		t.Errorf("%q: expected range error, got %v", "150%", err)
+	}
+}
+
+func BenchmarkParseTimestamp(b *testing.B) {
+	cases := []struct {
 		name  string
 		input string
 	}{
@@ -381,3 +2180,429 @@ func ExampleParseTimestamp() {
 	// Output:
 	// "2009-11-10 23:00:00 UTC" is a Tuesday.
 }
+
+func TestParseOrdinalDate(t *testing.T) {
+	cases := []struct {
+		input     string
+		expect    time.Time
+		expectErr bool
+	}{
+		{"2009-314", time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"2009-001", time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2009-365", time.Date(2009, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{"2008-060", time.Date(2008, 2, 29, 0, 0, 0, 0, time.UTC), false},
+		{"2008-366", time.Date(2008, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{"2000-366", time.Date(2000, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{"2009-366", time.Time{}, true},
+		{"1900-366", time.Time{}, true},
+		{"2009-000", time.Time{}, true},
+		{"2009-31", time.Time{}, true},
+		{"2009-3140", time.Time{}, true},
+		{"09-314", time.Time{}, true},
+		{"2009314", time.Time{}, true},
+		{"2009-314 UTC", time.Time{}, true},
+		{"2009-", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseOrdinalDate(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+}
+
+func TestParseUnix(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	cases := []struct {
+		input     string
+		millis    int64
+		micros    int64
+		nanos     int64
+		expectErr bool
+	}{
+		{"2009-11-10 23:00:00 UTC", 1257894000000, 1257894000000000, 1257894000000000000, false},
+		{"2009-11-10 23:00:00.123456789 UTC", 1257894000123, 1257894000123456, 1257894000123456789, false},
+		{"+1s", 1257894001000, 1257894001000000, 1257894001000000000, false},
+		{"today", 1257811200000, 1257811200000000, 1257811200000000000, false},
+		{"@0.5", 500, 500000, 500000000, false},
+		{"1969-12-31 23:59:59.9995 UTC", -1, -500, -500000, false},
+		{"invalid", 0, 0, 0, true},
+	}
+	for _, tc := range cases {
+		millis, errMillis := systemdtime.ParseUnixMillis(tc.input, now)
+		micros, errMicros := systemdtime.ParseUnixMicros(tc.input, now)
+		nanos, errNanos := systemdtime.ParseUnixNanos(tc.input, now)
+		if tc.expectErr {
+			if errMillis == nil || errMicros == nil || errNanos == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if errMillis != nil || errMicros != nil || errNanos != nil {
+			t.Errorf("%q: unexpected error: %v, %v, %v", tc.input, errMillis, errMicros, errNanos)
+			continue
+		}
+		if millis != tc.millis {
+			t.Errorf("%q: expected %d ms, got %d", tc.input, tc.millis, millis)
+		}
+		if micros != tc.micros {
+			t.Errorf("%q: expected %d us, got %d", tc.input, tc.micros, micros)
+		}
+		if nanos != tc.nanos {
+			t.Errorf("%q: expected %d ns, got %d", tc.input, tc.nanos, nanos)
+		}
+	}
+}
+
+func TestParseRFC5322Date(t *testing.T) {
+	cases := []struct {
+		input     string
+		expect    time.Time
+		expectErr bool
+	}{
+		// obsolete zones
+		{"Tue, 10 Nov 2009 18:15:22 EST", time.Date(2009, 11, 10, 23, 15, 22, 0, time.UTC), false},
+		{"Tue, 10 Nov 2009 18:15:22 EDT", time.Date(2009, 11, 10, 22, 15, 22, 0, time.UTC), false},
+		{"Tue, 10 Nov 2009 18:15:22 CST", time.Date(2009, 11, 11, 0, 15, 22, 0, time.UTC), false},
+		{"Tue, 10 Nov 2009 18:15:22 MDT", time.Date(2009, 11, 11, 0, 15, 22, 0, time.UTC), false},
+		{"Tue, 10 Nov 2009 18:15:22 PST", time.Date(2009, 11, 11, 2, 15, 22, 0, time.UTC), false},
+		{"Tue, 10 Nov 2009 18:15:22 UT", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"Tue, 10 Nov 2009 18:15:22 GMT", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"Tue, 10 Nov 2009 18:15:22 Z", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		// numeric offsets
+		{"Tue, 10 Nov 2009 18:15:22 +0100", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", 3600)), false},
+		{"Tue, 10 Nov 2009 18:15:22 -0530", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", -5*3600-30*60)), false},
+		{"Tue, 10 Nov 2009 18:15:22 -0000 (UTC)", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		// variations
+		{"10 Nov 2009 18:15 GMT", time.Date(2009, 11, 10, 18, 15, 0, 0, time.UTC), false},
+		{"tue, 10 nov 2009 18:15:22 gmt", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"Tue , 10 Nov 2009 18:15:22 GMT", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"Tue, 10 Nov 09 18:15:22 GMT", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"Wed, 10 Nov 99 18:15:22 GMT", time.Date(1999, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"Tue, 10 Nov 109 18:15:22 GMT", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"Tue, 10 Nov 2009\r\n 18:15:22\t+0100", time.Date(2009, 11, 10, 18, 15, 22, 0, time.FixedZone("", 3600)), false},
+		{"  Tue,  10 Nov 2009 18:15:22 GMT  ", time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		// error
+		{"", time.Time{}, true},
+		{"Mon, 10 Nov 2009 18:15:22 GMT", time.Time{}, true},
+		{"Tue 10 Nov 2009 18:15:22 GMT", time.Time{}, true},
+		{"Tue,, 10 Nov 2009 18:15:22 GMT", time.Time{}, true},
+		{"Tue , , 10 Nov 2009 18:15:22 GMT", time.Time{}, true},
+		{"Tue, 10 Foo 2009 18:15:22 GMT", time.Time{}, true},
+		{"Tue, 32 Nov 2009 18:15:22 GMT", time.Time{}, true},
+		{"Tue, 10 Nov 2009 18:15:22", time.Time{}, true},
+		{"Tue, 10 Nov 2009 18:15:22 XYZ", time.Time{}, true},
+		{"Tue, 10 Nov 2009 18:15:22 J", time.Time{}, true},
+		{"Tue, 10 Nov 2009 18:15:22 +01:00", time.Time{}, true},
+		{"Tue, 10 Nov 2009 1815 GMT", time.Time{}, true},
+		{"Tue, 10 Nov 2009 18:15:22 GMT (UTC", time.Time{}, true},
+		{"Tue, 10 Nov 2009 18:15:22 GMT extra", time.Time{}, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseRFC5322Date(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+}
+
+func TestParseFirst(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+	failFirst := func(string) (time.Time, error) { return time.Time{}, errFirst }
+	failSecond := func(string) (time.Time, error) { return time.Time{}, errSecond }
+
+	cases := []struct {
+		input     string
+		parsers   []func(string) (time.Time, error)
+		expect    time.Time
+		expectErr bool
+	}{
+		{"2009-314", []func(string) (time.Time, error){systemdtime.ParseRFC5322Date, systemdtime.ParseOrdinalDate}, time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"Tue, 10 Nov 2009 18:15:22 GMT", []func(string) (time.Time, error){systemdtime.ParseOrdinalDate, systemdtime.ParseRFC5322Date}, time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC), false},
+		{"2009-314", []func(string) (time.Time, error){failFirst, systemdtime.ParseOrdinalDate, failSecond}, time.Date(2009, 11, 10, 0, 0, 0, 0, time.UTC), false},
+		{"2009-314", nil, time.Time{}, true},
+		{"invalid", []func(string) (time.Time, error){systemdtime.ParseOrdinalDate, systemdtime.ParseRFC5322Date}, time.Time{}, true},
+	}
+	for _, tc := range cases {
+		got, err := systemdtime.ParseFirst(tc.input, tc.parsers...)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expect) {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expect, got)
+		}
+	}
+
+	_, err := systemdtime.ParseFirst("invalid", failFirst, failSecond)
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Errorf("expected error to wrap all failures, got %v", err)
+	}
+
+	_, err = systemdtime.ParseFirst("2009-13x", failFirst, systemdtime.ParseOrdinalDate)
+	var pe *systemdtime.ParseError
+	if !errors.As(err, &pe) {
+		t.Errorf("expected error to wrap *ParseError, got %v", err)
+	}
+}
+
+func TestErrEmptyInput(t *testing.T) {
+	inputs := []string{"", "  ", "\t\n", "\x00", " \t\x00\r\n\x7f\x1b "}
+	for _, input := range inputs {
+		if _, err := systemdtime.ParseTimespan(input); !errors.Is(err, systemdtime.ErrEmptyInput) {
+			t.Errorf("ParseTimespan(%q): expected ErrEmptyInput, got %v", input, err)
+		}
+		if _, err := systemdtime.ParseTimestamp(input); !errors.Is(err, systemdtime.ErrEmptyInput) {
+			t.Errorf("ParseTimestamp(%q): expected ErrEmptyInput, got %v", input, err)
+		}
+		if _, err := systemdtime.ParseOrdinalDate(input); !errors.Is(err, systemdtime.ErrEmptyInput) {
+			t.Errorf("ParseOrdinalDate(%q): expected ErrEmptyInput, got %v", input, err)
+		}
+		if _, err := systemdtime.ParseRFC5322Date(input); !errors.Is(err, systemdtime.ErrEmptyInput) {
+			t.Errorf("ParseRFC5322Date(%q): expected ErrEmptyInput, got %v", input, err)
+		}
+	}
+
+	// surrounding spaces are still fine where they were before
+	if _, err := systemdtime.ParseTimespan(" 5days  "); err != nil {
+		t.Errorf("ParseTimespan(%q): unexpected error: %v", " 5days  ", err)
+	}
+	if _, err := systemdtime.ParseTimespan("5\x00"); err == nil || errors.Is(err, systemdtime.ErrEmptyInput) {
+		t.Errorf("ParseTimespan(%q): expected non-empty input error, got %v", "5\x00", err)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	opts := systemdtime.ParseTimestampOptions{Lenient: true}
+	cases := []struct {
+		input string
+		pos   int
+	}{
+		{"2009-13-10", 5},
+		{"2009-11-32", 8},
+		{"2009-11-10 25:00", 11},
+		{"2009-11-10 18:61", 14},
+		{"2009-11-10 18:15:61", 17},
+		{"2009-11-10 18:15:22 Not/TZ", 20},
+		{"2009-11-10 18:15:22 +05:60", 24},
+		{"2009-11-10 18:15:22 abc", 20},
+		{"2009-11-10 18:15:22.5 x", 22},
+		{"09-11-10T18:15", 8},
+		{"Sat Sat 2009-11-14", 4},
+		{"today Not/TZ", 6},
+		{"+5abc", 2},
+		{"- 5min {{x}}", 7},
+		{"+3h -30min", 4},
+		{"1abc ago", 1},
+		{"@123abc", 4},
+		{"@@1.5x", 5},
+		{"@now+5abc", 6},
+		{"@0 Europe/London", 3},
+		{"start of fortnight", 9},
+		{"end of month 2009-13-01", 18},
+		{"50% of sometime", 7},
+		{"50% of today Not/TZ", 13},
+	}
+	for _, tc := range cases {
+		_, err := systemdtime.ParseTimestampWith(tc.input, opts, now)
+		var pe *systemdtime.ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("%q: expected *ParseError, got %v", tc.input, err)
+			continue
+		}
+		if pe.Input != tc.input || pe.Pos != tc.pos {
+			t.Errorf("%q: expected position %d in %q, got %d in %q", tc.input, tc.pos, tc.input, pe.Pos, pe.Input)
+		}
+		if pe.Error() != err.Error() {
+			t.Errorf("%q: expected message %q, got %q", tc.input, err.Error(), pe.Error())
+		}
+	}
+
+	// time spans
+	for input, pos := range map[string]int{"5min {{x}}": 5, "5 parsecs": 2, "0x5s": 0, "": 0} {
+		_, err := systemdtime.ParseTimespan(input)
+		var pe *systemdtime.ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("%q: expected *ParseError, got %v", input, err)
+			continue
+		}
+		if pe.Pos != pos {
+			t.Errorf("%q: expected position %d, got %d", input, pos, pe.Pos)
+		}
+	}
+
+	// other parse functions report positions in their whole input
+	others := []struct {
+		input string
+		parse func(string) error
+		pos   int
+	}{
+		{"Tue, 10 Nov 2009 25:15:22 +0100", func(s string) error { _, err := systemdtime.ParseRFC5322Date(s); return err }, 17},
+		{"Tue, 10 Nov 2009 18:15:22 +0199", func(s string) error { _, err := systemdtime.ParseRFC5322Date(s); return err }, 26},
+		{"Tue, 10 Foo 2009 18:15:22 +0100", func(s string) error { _, err := systemdtime.ParseRFC5322Date(s); return err }, 8},
+		{"10/5xyz", func(s string) error { _, _, _, err := systemdtime.ParseRate(s); return err }, 5},
+		{"10 / xyz", func(s string) error { _, _, _, err := systemdtime.ParseRate(s); return err }, 6},
+		{"2009-11-10T00:00:00Z/2009-13-10", func(s string) error { _, _, err := systemdtime.ParseInterval8601(s); return err }, 26},
+		{"2009-11-10T00:00:00Z/P1X", func(s string) error { _, _, err := systemdtime.ParseInterval8601(s); return err }, 23},
+		{"P1DT2X", func(s string) error { _, err := systemdtime.ParseISO8601Duration(s); return err }, 5},
+		{"Reiwa 1-13-10", func(s string) error { _, err := systemdtime.ParseJapaneseEra(s); return err }, 8},
+		{"Reiwa 1-11-10 x", func(s string) error { _, err := systemdtime.ParseJapaneseEra(s); return err }, 13},
+	}
+	for _, tc := range others {
+		err := tc.parse(tc.input)
+		var pe *systemdtime.ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("%q: expected *ParseError, got %v", tc.input, err)
+			continue
+		}
+		if pe.Input != tc.input || pe.Pos != tc.pos {
+			t.Errorf("%q: expected position %d in %q, got %d in %q", tc.input, tc.pos, tc.input, pe.Pos, pe.Input)
+		}
+	}
+
+	// wrapped errors are still reachable
+	_, err := systemdtime.ParseTimestamp("  ")
+	var pe *systemdtime.ParseError
+	if !errors.As(err, &pe) || !errors.Is(err, systemdtime.ErrEmptyInput) {
+		t.Errorf("%q: expected *ParseError wrapping ErrEmptyInput, got %v", "  ", err)
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	if got := systemdtime.MustParseTimespan("1h 30min"); got != 90*systemdtime.Minute {
+		t.Errorf("expected %v, got %v", 90*systemdtime.Minute, got)
+	}
+	if got := systemdtime.MustParseTimestamp("+1h", now); !got.Equal(now.Add(systemdtime.Hour)) {
+		t.Errorf("expected %v, got %v", now.Add(systemdtime.Hour), got)
+	}
+
+	cases := []struct {
+		name string
+		fn   func()
+	}{
+		{"MustParseTimespan", func() { systemdtime.MustParseTimespan("5 parsecs") }},
+		{"MustParseTimestamp", func() { systemdtime.MustParseTimestamp("2009-13-10", now) }},
+	}
+	for _, tc := range cases {
+		func() {
+			defer func() {
+				err, ok := recover().(error)
+				if !ok {
+					t.Errorf("%s: expected panic with error, got %v", tc.name, err)
+					return
+				}
+				var pe *systemdtime.ParseError
+				if !errors.As(err, &pe) {
+					t.Errorf("%s: expected *ParseError, got %v", tc.name, err)
+				}
+			}()
+			tc.fn()
+		}()
+	}
+}
+
+func TestMaxInputLength(t *testing.T) {
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	long := strings.Repeat("1s ", 100000)
+
+	// at the limit
+	span := systemdtime.ParseTimespanOptions{MaxInputLength: len("1h 30min")}
+	if d, err := systemdtime.ParseTimespanWith("1h 30min", span); err != nil || d != 90*systemdtime.Minute {
+		t.Errorf("%q: expected %v, got %v, %v", "1h 30min", 90*systemdtime.Minute, d, err)
+	}
+	stamp := systemdtime.ParseTimestampOptions{MaxInputLength: len("2009-11-10 18:15:22")}
+	if got, err := systemdtime.ParseTimestampWith("2009-11-10 18:15:22", stamp, now); err != nil ||
+		!got.Equal(time.Date(2009, 11, 10, 18, 15, 22, 0, time.UTC)) {
+		t.Errorf("%q: unexpected result %v, %v", "2009-11-10 18:15:22", got, err)
+	}
+
+	// over the limit
+	if _, err := systemdtime.ParseTimespanWith(long, span); !errors.Is(err, systemdtime.ErrInputTooLong) {
+		t.Errorf("expected ErrInputTooLong, got %v", err)
+	}
+	if _, err := systemdtime.ParseTimestampWith("2009-11-10 18:15:22 UTC", stamp, now); !errors.Is(err, systemdtime.ErrInputTooLong) {
+		t.Errorf("expected ErrInputTooLong, got %v", err)
+	}
+	if _, err := systemdtime.ParseTimestampWith("+"+long, stamp, now); !errors.Is(err, systemdtime.ErrInputTooLong) {
+		t.Errorf("expected ErrInputTooLong, got %v", err)
+	}
+
+	// no limit by default
+	if _, err := systemdtime.ParseTimespan(long); err != nil {
+		t.Errorf("unexpected error without limit: %v", err)
+	}
+
+	// negative limits are invalid
+	if _, err := systemdtime.ParseTimespanWith("1s", systemdtime.ParseTimespanOptions{MaxInputLength: -1}); err == nil {
+		t.Error("expected error for negative limit, got nil")
+	}
+	if _, err := systemdtime.ParseTimestampWith("now", systemdtime.ParseTimestampOptions{MaxInputLength: -1}, now); err == nil {
+		t.Error("expected error for negative limit, got nil")
+	}
+}
+
+func TestValid(t *testing.T) {
+	spans := []struct {
+		input  string
+		expect bool
+	}{
+		{"1h 30min", true},
+		{"0", true},
+		{"infinity", true},
+		{"", false},
+		{"5 parsecs", false},
+	}
+	for _, tc := range spans {
+		if got := systemdtime.ValidTimespan(tc.input); got != tc.expect {
+			t.Errorf("ValidTimespan(%q): expected %t, got %t", tc.input, tc.expect, got)
+		}
+	}
+
+	stamps := []struct {
+		input  string
+		expect bool
+	}{
+		{"now", true},
+		{"today", true},
+		{"2009-11-10 18:15:22 UTC", true},
+		{"@1234567890", true},
+		{"", false},
+		{"2009-13-10", false},
+		{"Wed 2009-11-10", false},
+	}
+	for _, tc := range stamps {
+		if got := systemdtime.ValidTimestamp(tc.input); got != tc.expect {
+			t.Errorf("ValidTimestamp(%q): expected %t, got %t", tc.input, tc.expect, got)
+		}
+	}
+}